@@ -0,0 +1,196 @@
+package consterr
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// Class categorises errors by how a caller should handle them,
+// independent of what exactly went wrong.
+type Class string
+
+// The classes of error, mapped to HTTP status codes by httptools.StatusCode
+// and to process exit codes by [ExitCode].
+const (
+	// Unclassified is the Class of an error which doesn't have one.
+	Unclassified    Class = ""
+	InvalidInput    Class = "invalid-input"
+	NotFound        Class = "not-found"
+	Conflict        Class = "conflict"
+	Unauthenticated Class = "unauthenticated"
+	Forbidden       Class = "forbidden"
+	// Retryable errors are transient, so the same request may later succeed.
+	Retryable Class = "retryable"
+	Internal  Class = "internal"
+)
+
+// Known reports if c is one of the classes above.
+// Other classes, such as from a typo like `not-fund/x: ...`, are allowed for custom handling,
+// but are not UserFacing, and map to the same exit and status codes as Unclassified,
+// so a test ranging over an app's Coded errors can check each class is Known.
+func (c Class) Known() bool {
+	switch c {
+	case InvalidInput, NotFound, Conflict, Unauthenticated, Forbidden, Retryable, Internal:
+		return true
+	}
+	return false
+}
+
+// UserFacing reports if errors of the Class are caused by the user,
+// and so their message is useful and safe to show them.
+func (c Class) UserFacing() bool {
+	switch c {
+	case InvalidInput, NotFound, Conflict, Unauthenticated, Forbidden:
+		return true
+	}
+	return false
+}
+
+// Coded is a const-compatible error with a stable machine-readable code and a Class,
+// written as `class/code: message`.
+//
+//	const ErrNoStream = consterr.Coded("not-found/no-stream: stream does not exist")
+//
+// A Coded without the `class/code: ` prefix is Unclassified, with no code.
+// The class is not checked against the constants, see [Class.Known].
+//
+//nolint:errname // Coded _is_ the type, as with Err.
+type Coded string
+
+// Error satisfies the error interface, with only the message.
+func (c Coded) Error() string {
+	_, msg := c.split()
+	return msg
+}
+
+// Class returns the Class of the error.
+func (c Coded) Class() Class {
+	prefix, _ := c.split()
+	class, _, _ := strings.Cut(prefix, "/")
+	return Class(class)
+}
+
+// Code returns the stable code of the error.
+func (c Coded) Code() string {
+	prefix, _ := c.split()
+	_, code, _ := strings.Cut(prefix, "/")
+	return code
+}
+
+// LogValue satisfies slog.LogValuer.
+func (c Coded) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("msg", c.Error()),
+		slog.String("code", c.Code()),
+		slog.String("class", string(c.Class())),
+	)
+}
+
+// Wrap is as per [Err.Wrap].
+func (c Coded) Wrap(cause error, args ...any) error {
+	return &Wrapped{Err: c, Cause: cause, Attrs: argsToAttrs(args), pcs: callers(0)}
+}
+
+// With is as per [Err.With].
+func (c Coded) With(args ...any) error {
+	return &Wrapped{Err: c, Attrs: argsToAttrs(args), pcs: callers(0)}
+}
+
+// split separates the `class/code` prefix from the message.
+func (c Coded) split() (string, string) {
+	prefix, msg, found := strings.Cut(string(c), ": ")
+	if !found || !strings.Contains(prefix, "/") || strings.Contains(prefix, " ") {
+		return "", string(c)
+	}
+	return prefix, msg
+}
+
+// classifier is implemented by any error with a Class, such as Coded.
+type classifier interface {
+	error
+	Class() Class
+}
+
+// coder is implemented by any error with a code, such as Coded.
+type coder interface {
+	error
+	Code() string
+}
+
+// ClassOf returns the Class of the first error in err's chain which has one,
+// in the order errors.As checks them, or Unclassified if none do.
+// As an error is checked before its cause,
+// a sentinel's Class takes precedence over that of anything it wraps,
+// unless the sentinel is itself Unclassified.
+func ClassOf(err error) Class {
+	class := Unclassified
+	walk(err, func(err error) bool {
+		if c, ok := err.(classifier); ok {
+			class = c.Class()
+		}
+		return class != Unclassified
+	})
+	return class
+}
+
+// CodeOf returns the code of the first error in err's chain which has one,
+// as per ClassOf, or empty if none do.
+func CodeOf(err error) string {
+	code := ""
+	walk(err, func(err error) bool {
+		if c, ok := err.(coder); ok {
+			code = c.Code()
+		}
+		return code != ""
+	})
+	return code
+}
+
+// walk calls fn for each error in err's chain, depth first as errors.As does,
+// until fn returns true, reporting if it did.
+// Unlike errors.As, it doesn't call any As methods.
+func walk(err error, fn func(error) bool) bool {
+	if err == nil {
+		return false
+	}
+	if fn(err) {
+		return true
+	}
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		return walk(u.Unwrap(), fn)
+	case interface{ Unwrap() []error }:
+		for _, err := range u.Unwrap() {
+			if walk(err, fn) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ExitCode maps err to a process exit code per its Class,
+// using the BSD sysexits.h conventions, for a CLI's main.
+// A nil err is 0, and an Unclassified err, or one of a Class which isn't Known, is 1.
+//
+//nolint:gomnd // the exit codes are the point.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	switch ClassOf(err) {
+	case InvalidInput:
+		return 65 // EX_DATAERR
+	case NotFound:
+		return 66 // EX_NOINPUT
+	case Conflict:
+		return 73 // EX_CANTCREAT
+	case Unauthenticated, Forbidden:
+		return 77 // EX_NOPERM
+	case Retryable:
+		return 75 // EX_TEMPFAIL
+	case Internal:
+		return 70 // EX_SOFTWARE
+	}
+	return 1
+}
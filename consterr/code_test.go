@@ -0,0 +1,141 @@
+package consterr_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"importfromprojectlocally/consterr"
+)
+
+const (
+	errNoStream = consterr.Coded("not-found/no-stream: stream does not exist")
+	errBusy     = consterr.Coded("retryable/busy: too many requests: try later")
+	errPlain    = consterr.Coded("no prefix: just a message")
+)
+
+func TestCoded(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		err   consterr.Coded
+		msg   string
+		code  string
+		class consterr.Class
+	}{
+		"coded":        {errNoStream, "stream does not exist", "no-stream", consterr.NotFound},
+		"colon in msg": {errBusy, "too many requests: try later", "busy", consterr.Retryable},
+		"no prefix":    {errPlain, "no prefix: just a message", "", consterr.Unclassified},
+		"no separator": {consterr.Coded("internal/oops"), "internal/oops", "", consterr.Unclassified},
+		"custom class": {consterr.Coded("quota/over-limit: over limit"), "over limit", "over-limit", consterr.Class("quota")},
+	}
+
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+			is.Equal(tc.err.Error(), tc.msg)
+			is.Equal(tc.err.Code(), tc.code)
+			is.Equal(tc.err.Class(), tc.class)
+		})
+	}
+}
+
+func TestClassOf(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		err   error
+		class consterr.Class
+		code  string
+		exit  int
+	}{
+		"nil":          {nil, consterr.Unclassified, "", 0},
+		"unclassified": {errors.New("boom"), consterr.Unclassified, "", 1},
+		"direct":       {errNoStream, consterr.NotFound, "no-stream", 66},
+		"fmt wrapped":  {fmt.Errorf("get: %w", errBusy), consterr.Retryable, "busy", 75},
+		"sentinel wins over cause": {
+			consterr.Coded("invalid-input/bad-name: bad name").Wrap(errBusy),
+			consterr.InvalidInput, "bad-name", 65,
+		},
+		"found through Err": {
+			errNoConfig.Wrap(fmt.Errorf("reading: %w", errNoStream)),
+			consterr.NotFound, "no-stream", 66,
+		},
+		"joined": {errors.Join(errors.New("boom"), errBusy), consterr.Retryable, "busy", 75},
+		"unclassified sentinel skipped": {
+			consterr.Coded("plain").Wrap(errNoStream),
+			consterr.NotFound, "no-stream", 66,
+		},
+		"unknown class": {
+			consterr.Coded("not-fund/no-stream: stream does not exist"),
+			consterr.Class("not-fund"), "no-stream", 1,
+		},
+	}
+
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+			is.Equal(consterr.ClassOf(tc.err), tc.class)
+			is.Equal(consterr.CodeOf(tc.err), tc.code)
+			is.Equal(consterr.ExitCode(tc.err), tc.exit)
+		})
+	}
+}
+
+func TestClassUserFacing(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	is.True(consterr.InvalidInput.UserFacing())
+	is.True(consterr.NotFound.UserFacing())
+	is.True(!consterr.Internal.UserFacing())
+	is.True(!consterr.Retryable.UserFacing())
+	is.True(!consterr.Unclassified.UserFacing())
+	is.True(!consterr.Class("not-fund").UserFacing()) // unknown classes are not user facing
+}
+
+func TestClassKnown(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	is.True(consterr.NotFound.Known())
+	is.True(consterr.Internal.Known())
+	is.True(!consterr.Unclassified.Known())
+	is.True(!consterr.Class("not-fund").Known()) // a typo
+	is.True(!consterr.Coded("quota/over-limit: over limit").Class().Known())
+}
+
+func TestCodedLogValue(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	buf := &bytes.Buffer{}
+	log := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	log.Info("test", slog.Any("error", errNoStream), slog.Any("wrapped", errNoStream.With("stream", "logs")))
+
+	expected := `level=INFO msg=test ` +
+		`error.msg="stream does not exist" error.code=no-stream error.class=not-found ` +
+		`wrapped.msg="stream does not exist stream=logs" wrapped.err="stream does not exist" ` +
+		`wrapped.code=no-stream wrapped.class=not-found wrapped.stream=logs`
+	if tracing() {
+		is.True(strings.HasPrefix(buf.String(), expected+" wrapped.stack.0.func=")) // With adds its stack
+		return
+	}
+	is.Equal(buf.String(), expected+"\n")
+}
@@ -6,6 +6,8 @@ package consterr
 //
 // Err does not implement Unwrap, Is or As as used by [errors];
 // it is for "fundamental" error types.
+// To add context while still matching via errors.Is, use [Err.Wrap] or [Err.With].
+// For an error with a code and [Class], use [Coded].
 //
 //nolint:errname // Err _is_ the type, not XXXError or similar.
 type Err string
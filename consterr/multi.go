@@ -0,0 +1,137 @@
+package consterr
+
+import (
+	"encoding/json"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Errors aggregates several errors, such as from validating many things at once.
+// As with errors.Join, errors.Is and errors.As search every error.
+//
+//nolint:errname // Errors _is_ the type, as with Err.
+type Errors []error
+
+// Join returns the non-nil errs as Errors, in order,
+// flattening any which are themselves Errors.
+// It returns nil if every err is nil, and a lone err as-is.
+func Join(errs ...error) error {
+	joined := Errors{}
+	for _, err := range errs {
+		switch e := err.(type) {
+		case nil:
+		case Errors:
+			joined = append(joined, e...)
+		default:
+			joined = append(joined, err)
+		}
+	}
+	switch len(joined) {
+	case 0:
+		return nil
+	case 1:
+		return joined[0]
+	}
+	return joined
+}
+
+// Error satisfies the error interface, listing each error on its own line,
+// with the lines of any multi-line message indented beneath it.
+//
+//	2 errors:
+//	  - first error
+//	  - second error
+func (e Errors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	msg := strings.Builder{}
+	msg.WriteString(strconv.Itoa(len(e)) + " errors:")
+	for _, err := range e {
+		msg.WriteString("\n  - " + strings.ReplaceAll(err.Error(), "\n", "\n    "))
+	}
+	return msg.String()
+}
+
+// Unwrap returns the errors, for errors.Is and errors.As.
+func (e Errors) Unwrap() []error {
+	return e
+}
+
+// MarshalJSON satisfies json.Marshaler as an array,
+// of each error's own JSON if it is a json.Marshaler, otherwise its message.
+func (e Errors) MarshalJSON() ([]byte, error) {
+	items := make([]any, 0, len(e))
+	for _, err := range e {
+		if m, ok := err.(json.Marshaler); ok {
+			items = append(items, m)
+		} else {
+			items = append(items, err.Error())
+		}
+	}
+	return json.Marshal(items)
+}
+
+// LogValue satisfies slog.LogValuer as a group keyed by index,
+// rendering any error which is a slog.LogValuer as its own group.
+func (e Errors) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, len(e))
+	for i, err := range e {
+		attrs = append(attrs, slog.Any(strconv.Itoa(i), err))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// Collector gathers errors from several goroutines.
+// The zero value is ready to use, and it must not be copied after first use.
+//
+//	c := consterr.Collector{}
+//	for _, s := range servers {
+//		c.Go(func() error { return s.Shutdown(ctx) })
+//	}
+//	return c.Wait()
+type Collector struct {
+	mu   sync.Mutex
+	wg   sync.WaitGroup
+	errs []error
+}
+
+// Add collects err, ignoring nil. It is safe to call from any goroutine.
+func (c *Collector) Add(err error) {
+	if err == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.errs = append(c.errs, err)
+}
+
+// Go runs fn in a new goroutine, collecting any error it returns.
+func (c *Collector) Go(fn func() error) {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.Add(fn())
+	}()
+}
+
+// Wait waits for every fn passed to Go to return, then returns Err.
+func (c *Collector) Wait() error {
+	c.wg.Wait()
+	return c.Err()
+}
+
+// Err joins the errors collected so far, per [Join].
+// As goroutines finish in any order, the errors are sorted by message,
+// so the result is deterministic.
+func (c *Collector) Err() error {
+	c.mu.Lock()
+	errs := append([]error{}, c.errs...)
+	c.mu.Unlock()
+
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Error() < errs[j].Error() })
+	return Join(errs...)
+}
@@ -0,0 +1,127 @@
+package consterr_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"importfromprojectlocally/consterr"
+)
+
+func TestJoin(t *testing.T) {
+	t.Parallel()
+
+	first := errors.New("first")
+	second := errors.New("second\nwith detail")
+	third := errNoConfig.With("path", "/etc")
+
+	testCases := map[string]struct {
+		errs   []error
+		expect error
+		msg    string
+	}{
+		"none": {nil, nil, ""},
+		"nils": {[]error{nil, nil}, nil, ""},
+		"one":  {[]error{nil, first}, first, "first"},
+		"many": {[]error{first, nil, third}, consterr.Errors{first, third}, "2 errors:\n  - first\n  - no config path=/etc"},
+		"multi-line": {
+			[]error{first, second},
+			consterr.Errors{first, second},
+			"2 errors:\n  - first\n  - second\n    with detail",
+		},
+		"flattened": {
+			[]error{first, consterr.Join(second, third)},
+			consterr.Errors{first, second, third},
+			"3 errors:\n  - first\n  - second\n    with detail\n  - no config path=/etc",
+		},
+	}
+
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			err := consterr.Join(tc.errs...)
+			is.Equal(err, tc.expect)
+			if err != nil {
+				is.Equal(err.Error(), tc.msg)
+			}
+		})
+	}
+}
+
+func TestErrorsIsAs(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	pathErr := &fs.PathError{Op: "open", Path: "/etc", Err: fs.ErrPermission}
+	err := fmt.Errorf("starting: %w", consterr.Join(errors.New("boom"), errNoStream.Wrap(pathErr)))
+
+	is.True(errors.Is(err, errNoStream))      // sentinel anywhere in the list
+	is.True(errors.Is(err, fs.ErrPermission)) // cause of an item
+	var target *fs.PathError
+	is.True(errors.As(err, &target))
+	is.Equal(consterr.ClassOf(err), consterr.NotFound)
+}
+
+func TestErrorsEncoding(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	err := consterr.Join(errors.New("boom"), errNoStream.With("stream", "logs"))
+
+	js, jsErr := json.Marshal(err)
+	is.NoErr(jsErr)
+	is.Equal(string(js), `["boom","stream does not exist stream=logs"]`)
+
+	buf := &bytes.Buffer{}
+	log := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	log.Info("test", slog.Any("error", err))
+	expected := `{"level":"INFO","msg":"test","error":{"0":"boom","1":{` +
+		`"msg":"stream does not exist stream=logs","err":"stream does not exist",` +
+		`"code":"no-stream","class":"not-found","stream":"logs"`
+	if tracing() {
+		is.True(strings.HasPrefix(buf.String(), expected+`,"stack":{`)) // With adds its stack
+		return
+	}
+	is.Equal(buf.String(), expected+"}}}\n")
+}
+
+func TestCollector(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	c := consterr.Collector{}
+	is.NoErr(c.Wait()) // nothing collected
+
+	for i := 9; i >= 0; i-- {
+		i := i
+		c.Go(func() error {
+			if i%2 == 0 {
+				return nil
+			}
+			return errors.New("failed " + strconv.Itoa(i))
+		})
+	}
+	c.Add(nil)
+	c.Add(errors.New("added"))
+
+	err := c.Wait()
+	is.Equal(err.Error(), "6 errors:\n  - added\n  - failed 1\n  - failed 3\n  - failed 5\n  - failed 7\n  - failed 9")
+}
@@ -0,0 +1,111 @@
+package consterr
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// Trace records where err was returned from, when built with `-tags consterrtrace`,
+// so logs can point to the origin of a sentinel passed up through several returns.
+// Without the tag, Trace returns err as-is, so it can be left in place at no cost.
+//
+//	return consterr.Trace(ErrNoArgs)
+//
+// [Err.Wrap] and [Err.With] record the stack in the same way.
+func Trace(err error) error {
+	pcs := callers(0)
+	if err == nil || len(pcs) == 0 {
+		return err
+	}
+	return &Traced{Err: err, pcs: pcs}
+}
+
+// Traced is an error with the stack of where it was passed to [Trace].
+//
+//nolint:errname // named for what it is, alongside Wrapped.
+type Traced struct {
+	Err error
+	pcs []uintptr
+}
+
+// Error satisfies the error interface, unchanged from Err.
+func (t *Traced) Error() string {
+	return t.Err.Error()
+}
+
+// Unwrap returns Err, for errors.Is and errors.As.
+func (t *Traced) Unwrap() error {
+	return t.Err
+}
+
+// Frames returns the stack recorded by Trace, innermost first.
+func (t *Traced) Frames() []runtime.Frame {
+	return frames(t.pcs)
+}
+
+// LogValue satisfies slog.LogValuer, as the group of Err if it is a slog.LogValuer,
+// or otherwise its message, with the stack added as a group of frames,
+// unless the group already has one, such as from [Err.Wrap], which is kept as the more precise.
+func (t *Traced) LogValue() slog.Value {
+	attrs := []slog.Attr{slog.String("msg", t.Error())}
+	if lv, ok := t.Err.(slog.LogValuer); ok {
+		if v := lv.LogValue().Resolve(); v.Kind() == slog.KindGroup {
+			attrs = v.Group()
+		}
+	}
+	for _, a := range attrs {
+		if a.Key == "stack" {
+			return slog.GroupValue(attrs...)
+		}
+	}
+	return slog.GroupValue(append(attrs, stackAttr(t.pcs))...)
+}
+
+// Format satisfies fmt.Formatter, adding the stack for `%+v`,
+// one frame per line as function then file:line, as in a panic.
+func (t *Traced) Format(s fmt.State, verb rune) {
+	_, _ = fmt.Fprint(s, t.Error())
+	if verb == 'v' && s.Flag('+') {
+		_, _ = fmt.Fprint(s, formatFrames(t.pcs))
+	}
+}
+
+// frames resolves pcs, dropping those in the runtime, such as runtime.main.
+func frames(pcs []uintptr) []runtime.Frame {
+	out := []runtime.Frame{}
+	iter := runtime.CallersFrames(pcs)
+	for {
+		f, more := iter.Next()
+		if f.Function != "" && !strings.HasPrefix(f.Function, "runtime.") {
+			out = append(out, f)
+		}
+		if !more {
+			return out
+		}
+	}
+}
+
+// stackAttr renders pcs as a group of frames keyed by index.
+func stackAttr(pcs []uintptr) slog.Attr {
+	attrs := []slog.Attr{}
+	for i, f := range frames(pcs) {
+		attrs = append(attrs, slog.Group(strconv.Itoa(i),
+			slog.String("func", f.Function),
+			slog.String("file", f.File),
+			slog.Int("line", f.Line),
+		))
+	}
+	return slog.Attr{Key: "stack", Value: slog.GroupValue(attrs...)}
+}
+
+// formatFrames renders pcs as lines of function, then indented file:line.
+func formatFrames(pcs []uintptr) string {
+	out := strings.Builder{}
+	for _, f := range frames(pcs) {
+		fmt.Fprintf(&out, "\n%s\n\t%s:%d", f.Function, f.File, f.Line)
+	}
+	return out.String()
+}
@@ -0,0 +1,9 @@
+//go:build !consterrtrace
+
+package consterr
+
+// callers records nothing without the consterrtrace build tag,
+// so tracing costs nothing unless enabled.
+func callers(int) []uintptr {
+	return nil
+}
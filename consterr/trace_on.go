@@ -0,0 +1,15 @@
+//go:build consterrtrace
+
+package consterr
+
+import "runtime"
+
+// maxFrames limits how much of the stack is recorded.
+const maxFrames = 32
+
+// callers records the stack above the caller of the function calling callers,
+// skipping skip more frames.
+func callers(skip int) []uintptr {
+	pcs := make([]uintptr, maxFrames)
+	return pcs[:runtime.Callers(skip+3, pcs)]
+}
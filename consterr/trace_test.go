@@ -0,0 +1,76 @@
+package consterr_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"importfromprojectlocally/consterr"
+)
+
+// tracing reports if the tests were built with `-tags consterrtrace`.
+func tracing() bool {
+	return consterr.Trace(errNoConfig) != error(errNoConfig)
+}
+
+func TestTrace(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	is.Equal(consterr.Trace(nil), nil)
+
+	err := consterr.Trace(errNoConfig)
+	is.Equal(err.Error(), "no config")   // message unchanged
+	is.True(errors.Is(err, errNoConfig)) // still matches
+	if !tracing() {
+		is.Equal(err, error(errNoConfig)) // returned as-is
+		return
+	}
+
+	var traced *consterr.Traced
+	is.True(errors.As(err, &traced))
+	frames := traced.Frames()
+	is.True(len(frames) > 0)
+	is.Equal(frames[0].Function, "importfromprojectlocally/consterr_test.TestTrace") // where Trace was called
+	is.True(strings.HasSuffix(frames[0].File, "consterr/trace_test.go"))
+
+	_, _, line, _ := runtime.Caller(0)
+	wrapped := errInvalid.With("field", "port").(*consterr.Wrapped)
+	is.Equal(wrapped.Frames()[0].Line, line+1) // With records its call site too
+
+	is.True(strings.HasPrefix(fmt.Sprintf("%+v", consterr.Trace(errNoConfig)), "no config\nimportfromprojectlocally/consterr_test.TestTrace\n\t"))
+	is.Equal(fmt.Sprintf("%v", consterr.Trace(errNoConfig)), "no config")
+}
+
+func TestTraceLogValue(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	buf := &bytes.Buffer{}
+	log := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey || a.Key == "file" || a.Key == "line" {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	log.Info("test", "error", consterr.Trace(errInvalid.With("field", "port")), "plain", consterr.Trace(errNoConfig))
+
+	if !tracing() {
+		is.Equal(buf.String(), `level=INFO msg=test error.msg="invalid config field=port" error.err="invalid config" error.field=port plain="no config"`+"\n")
+		return
+	}
+	// The group of the traced error is kept, with only its own stack.
+	is.True(strings.HasPrefix(buf.String(), `level=INFO msg=test error.msg="invalid config field=port" error.err="invalid config" error.field=port `+
+		`error.stack.0.func=importfromprojectlocally/consterr_test.TestTraceLogValue `))
+	is.Equal(strings.Count(buf.String(), "error.stack.0.func="), 1)
+	// An error without a group gets the stack of Trace.
+	is.True(strings.Contains(buf.String(), ` plain.msg="no config" plain.stack.0.func=importfromprojectlocally/consterr_test.TestTraceLogValue `))
+}
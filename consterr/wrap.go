@@ -0,0 +1,93 @@
+package consterr
+
+import (
+	"log/slog"
+	"runtime"
+	"strings"
+)
+
+// Wrapped is a sentinel, an Err or Coded, with a cause and structured details attached,
+// as created by [Err.Wrap] and [Err.With].
+// errors.Is matches both the sentinel and the cause,
+// and errors.As searches both.
+//
+//nolint:errname // named for what it is, alongside Err.
+type Wrapped struct {
+	Err   error
+	Cause error
+	Attrs []slog.Attr
+	// pcs is the stack recorded when built with the consterrtrace tag, per Trace.
+	pcs []uintptr
+}
+
+// Wrap returns an error matching e for errors.Is, carrying cause,
+// along with any details given as slog style key value pairs or slog.Attrs.
+//
+//	return ErrNoConfig.Wrap(err, "path", path)
+func (e Err) Wrap(cause error, args ...any) error {
+	return &Wrapped{Err: e, Cause: cause, Attrs: argsToAttrs(args), pcs: callers(0)}
+}
+
+// With returns an error matching e for errors.Is,
+// carrying details given as slog style key value pairs or slog.Attrs.
+//
+//	return ErrNoArgs.With("argc", len(args))
+func (e Err) With(args ...any) error {
+	return &Wrapped{Err: e, Attrs: argsToAttrs(args), pcs: callers(0)}
+}
+
+// Error satisfies the error interface, as the sentinel followed by any details and cause,
+// ie `no config path=/etc/myapp.conf: open /etc/myapp.conf: no such file or directory`.
+func (w *Wrapped) Error() string {
+	msg := strings.Builder{}
+	msg.WriteString(w.Err.Error())
+	for _, a := range w.Attrs {
+		msg.WriteString(" " + a.Key + "=" + a.Value.Resolve().String())
+	}
+	if w.Cause != nil {
+		msg.WriteString(": " + w.Cause.Error())
+	}
+	return msg.String()
+}
+
+// Unwrap returns the sentinel and the cause, if any, for errors.Is and errors.As.
+func (w *Wrapped) Unwrap() []error {
+	if w.Cause == nil {
+		return []error{w.Err}
+	}
+	return []error{w.Err, w.Cause}
+}
+
+// LogValue satisfies slog.LogValuer, rendering the error as a group of
+// the full message, the sentinel alone for easy filtering, its code and class if Coded,
+// the details, the cause, and the stack if recorded per [Trace].
+// A cause which is itself a slog.LogValuer, such as another Wrapped, is nested.
+func (w *Wrapped) LogValue() slog.Value {
+	attrs := make([]slog.Attr, 0, len(w.Attrs)+5)
+	attrs = append(attrs, slog.String("msg", w.Error()), slog.String("err", w.Err.Error()))
+	if c, ok := w.Err.(Coded); ok {
+		attrs = append(attrs, slog.String("code", c.Code()), slog.String("class", string(c.Class())))
+	}
+	attrs = append(attrs, w.Attrs...)
+	if w.Cause != nil {
+		attrs = append(attrs, slog.Any("cause", w.Cause))
+	}
+	if len(w.pcs) > 0 {
+		attrs = append(attrs, stackAttr(w.pcs))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// Frames returns the stack recorded by Wrap or With, innermost first,
+// which is empty unless built with the consterrtrace tag, per [Trace].
+func (w *Wrapped) Frames() []runtime.Frame {
+	return frames(w.pcs)
+}
+
+// argsToAttrs converts slog style args to attrs, exactly as slog would.
+func argsToAttrs(args []any) []slog.Attr {
+	if len(args) == 0 {
+		return nil
+	}
+	return slog.Group("", args...).Value.Group()
+}
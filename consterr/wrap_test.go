@@ -0,0 +1,83 @@
+package consterr_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"importfromprojectlocally/consterr"
+)
+
+const (
+	errNoConfig = consterr.Err("no config")
+	errInvalid  = consterr.Err("invalid config")
+)
+
+func TestWrap(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	cause := &fs.PathError{Op: "open", Path: "/etc/myapp.conf", Err: fs.ErrNotExist}
+	err := fmt.Errorf("loading: %w", errNoConfig.Wrap(cause, "path", "/etc/myapp.conf", slog.Int("attempt", 2)))
+
+	is.Equal(err.Error(), "loading: no config path=/etc/myapp.conf attempt=2: open /etc/myapp.conf: file does not exist")
+	is.True(errors.Is(err, errNoConfig))    // matches the sentinel
+	is.True(errors.Is(err, fs.ErrNotExist)) // and the cause chain
+	is.True(!errors.Is(err, errInvalid))    // but not other sentinels
+
+	var pathErr *fs.PathError
+	is.True(errors.As(err, &pathErr)) // finds the cause
+	is.Equal(pathErr.Path, "/etc/myapp.conf")
+
+	var wrapped *consterr.Wrapped
+	is.True(errors.As(err, &wrapped)) // finds the details
+	is.Equal(wrapped.Err, errNoConfig)
+	is.Equal(len(wrapped.Attrs), 2)
+}
+
+func TestWith(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	err := errInvalid.With("field", "port", "value", 0)
+	is.Equal(err.Error(), "invalid config field=port value=0")
+	is.True(errors.Is(err, errInvalid))
+	is.Equal(errors.Unwrap(err), nil) // multiple unwrap only
+
+	nested := errNoConfig.Wrap(err)
+	is.Equal(nested.Error(), "no config: invalid config field=port value=0")
+	is.True(errors.Is(nested, errInvalid))
+}
+
+func TestWrappedLogValue(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	buf := &bytes.Buffer{}
+	log := slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	err := errNoConfig.Wrap(errInvalid.With("field", "port"), "path", "/etc/myapp.conf")
+	log.Info("test", slog.Any("error", err))
+
+	expected := `{"level":"INFO","msg":"test","error":{` +
+		`"msg":"no config path=/etc/myapp.conf: invalid config field=port","err":"no config","path":"/etc/myapp.conf",` +
+		`"cause":{"msg":"invalid config field=port","err":"invalid config","field":"port"`
+	if tracing() {
+		is.True(strings.HasPrefix(buf.String(), expected+`,"stack":{`)) // the cause's stack
+		is.Equal(strings.Count(buf.String(), `"stack":`), 2)            // then the wrapper's
+		return
+	}
+	is.Equal(buf.String(), expected+"}}}\n")
+}
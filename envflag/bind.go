@@ -0,0 +1,209 @@
+package envflag
+
+import (
+	"encoding"
+	"flag"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Bind registers a flag in fs for every exported field of the struct cfg points to,
+// returning the Options needed by [Parse] to honour the field tags.
+// Field tags are:
+//   - `flag:"name"` names the flag, otherwise the field name is converted to kebab case,
+//     ie `LogLevel` is `log-level`; `flag:"-"` skips the field.
+//   - `default:"value"` is the default, otherwise the field's current value is.
+//     For a Slice or Map it is split on commas, as an env var would be.
+//   - `usage:"text"` is the flag usage text.
+//   - `env:"KEY"` reads the flag from exactly the env var KEY, per [WithEnvKey].
+//   - `envflag:"required,sensitive,list,reloadable"` applies [WithRequired], [WithSensitive],
+//     [WithList], or [WithReloadable].
+//
+// Fields may be any of string, bool, int, int64, uint, uint64, float64, time.Duration,
+// url.URL, or implement flag.Value or encoding.TextUnmarshaler, such as slog.LevelVar or [ByteSize].
+// Slices of the basic types and time.Duration are a [Slice],
+// and map[string]string or map[string]int a [Map].
+// Nil pointers to those types are allocated.
+// Fields that are structs of any other type are nested,
+// with their flags named `parent.child`, so env var `PARENT_CHILD`.
+func Bind(fs *flag.FlagSet, cfg any) ([]Option, error) {
+	rv := reflect.ValueOf(cfg)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("binding flags: %T is not a pointer to a struct", cfg)
+	}
+	opts := []Option{}
+	if err := bindStruct(fs, rv.Elem(), "", &opts); err != nil {
+		return nil, fmt.Errorf("binding flags: %w", err)
+	}
+	return opts, nil
+}
+
+// ParseStruct is [Bind] followed by [Parse],
+// with any opts applied after those from the struct tags.
+//
+//	cfg := Config{}
+//	fs := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+//	err := envflag.ParseStruct(fs, &cfg, os.Args[1:], os.Environ(), envflag.WithPrefix("myapp"))
+//
+// The options from the tags are not returned, so [LogFlags], [Usage], [WriteMarkdown],
+// and [WriteEnvExample] given only opts would miss tags such as `sensitive`, and show secrets.
+// Callers needing any of them should use Bind and Parse, passing every function the same options:
+//
+//	opts, err := envflag.Bind(fs, &cfg)
+//	opts = append(opts, envflag.WithPrefix("myapp"))
+//	fs.Usage = envflag.Usage(fs, os.Environ(), opts...)
+//	err = envflag.Parse(fs, os.Args[1:], os.Environ(), opts...)
+func ParseStruct(fs *flag.FlagSet, cfg any, args, environ []string, opts ...Option) error {
+	tagOpts, err := Bind(fs, cfg)
+	if err != nil {
+		return err
+	}
+	return Parse(fs, args, environ, append(tagOpts, opts...)...)
+}
+
+// WithEnvKey reads flag name from exactly the environment variable key,
+// ignoring any prefix, rather than from the name derived from the flag.
+func WithEnvKey(name, key string) Option {
+	return func(o *options) {
+		o.envOverrides[name] = key
+	}
+}
+
+//nolint:gocognit,cyclop // a flat type switch is easier to follow than splitting it up.
+func bindStruct(fs *flag.FlagSet, rv reflect.Value, prefix string, opts *[]Option) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name, ok := field.Tag.Lookup("flag")
+		if !field.IsExported() || name == "-" {
+			continue
+		}
+		if !ok || name == "" {
+			name = kebabCase(field.Name)
+		}
+		name = prefix + name
+		usage := field.Tag.Get("usage")
+
+		fv := rv.Field(i)
+		if fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				fv.Set(reflect.New(fv.Type().Elem()))
+			}
+			fv = fv.Elem()
+		}
+		ptr := fv.Addr().Interface()
+
+		// list creates the Slice or Map for list fields, which are registered after the switch.
+		var list func() flag.Value
+		switch v := ptr.(type) {
+		case flag.Value:
+			fs.Var(v, name, usage)
+		case *time.Duration:
+			fs.DurationVar(v, name, *v, usage)
+		case *url.URL:
+			fs.Var(NewURL(v), name, usage)
+		case encoding.TextUnmarshaler:
+			if m, ok := ptr.(encoding.TextMarshaler); ok {
+				fs.TextVar(v, name, m, usage)
+			} else {
+				fs.Func(name, usage, func(s string) error { return v.UnmarshalText([]byte(s)) })
+			}
+		case *string:
+			fs.StringVar(v, name, *v, usage)
+		case *bool:
+			fs.BoolVar(v, name, *v, usage)
+		case *int:
+			fs.IntVar(v, name, *v, usage)
+		case *int64:
+			fs.Int64Var(v, name, *v, usage)
+		case *uint:
+			fs.UintVar(v, name, *v, usage)
+		case *uint64:
+			fs.Uint64Var(v, name, *v, usage)
+		case *float64:
+			fs.Float64Var(v, name, *v, usage)
+		case *[]string:
+			list = func() flag.Value { return NewSlice(v, nil) }
+		case *[]bool:
+			list = func() flag.Value { return NewSlice(v, nil) }
+		case *[]int:
+			list = func() flag.Value { return NewSlice(v, nil) }
+		case *[]int64:
+			list = func() flag.Value { return NewSlice(v, nil) }
+		case *[]uint:
+			list = func() flag.Value { return NewSlice(v, nil) }
+		case *[]uint64:
+			list = func() flag.Value { return NewSlice(v, nil) }
+		case *[]float64:
+			list = func() flag.Value { return NewSlice(v, nil) }
+		case *[]time.Duration:
+			list = func() flag.Value { return NewSlice(v, nil) }
+		case *map[string]string:
+			list = func() flag.Value { return NewMap(v, nil) }
+		case *map[string]int:
+			list = func() flag.Value { return NewMap(v, nil) }
+		default:
+			if fv.Kind() != reflect.Struct {
+				return fmt.Errorf("field %v: unsupported type %v", field.Name, field.Type)
+			}
+			if err := bindStruct(fs, fv, name+".", opts); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if list != nil {
+			// the default is Set on a throwaway list, so the first Set of the flag still replaces it.
+			if def, ok := field.Tag.Lookup("default"); ok {
+				if err := list().Set(def); err != nil {
+					return fmt.Errorf("field %v: invalid default %q: %w", field.Name, def, err)
+				}
+			}
+			fs.Var(list(), name, usage)
+		} else if def, ok := field.Tag.Lookup("default"); ok {
+			f := fs.Lookup(name)
+			if err := f.Value.Set(def); err != nil {
+				return fmt.Errorf("field %v: invalid default %q: %w", field.Name, def, err)
+			}
+			f.DefValue = f.Value.String()
+		}
+		if key := field.Tag.Get("env"); key != "" {
+			*opts = append(*opts, WithEnvKey(name, key))
+		}
+		if hasTagOption(field, "required") {
+			*opts = append(*opts, WithRequired(name))
+		}
+		if hasTagOption(field, "sensitive") {
+			*opts = append(*opts, WithSensitive(name))
+		}
+		if hasTagOption(field, "list") {
+			*opts = append(*opts, WithList(name))
+		}
+		if hasTagOption(field, "reloadable") {
+			*opts = append(*opts, WithReloadable(name))
+		}
+	}
+	return nil
+}
+
+// kebabCase converts a Go field name to a flag name,
+// ie `LogLevel` to `log-level` and `APIKey` to `api-key`.
+func kebabCase(s string) string {
+	runes := []rune(s)
+	out := strings.Builder{}
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prevLower := !unicode.IsUpper(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || nextLower {
+				out.WriteRune('-')
+			}
+		}
+		out.WriteRune(unicode.ToLower(r))
+	}
+	return out.String()
+}
@@ -0,0 +1,224 @@
+package envflag_test
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"log/slog"
+	"net/netip"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+
+	"importfromprojectlocally/envflag"
+)
+
+// upperText only implements encoding.TextUnmarshaler.
+type upperText string
+
+func (u *upperText) UnmarshalText(b []byte) error {
+	*u = upperText(strings.ToUpper(string(b)))
+	return nil
+}
+
+type bindDB struct {
+	URL      url.URL `usage:"database url" envflag:"required"`
+	Password string  `envflag:"sensitive"`
+	Pool     *bindPool
+}
+
+type bindPool struct {
+	Size int `default:"4"`
+}
+
+type bindConfig struct {
+	Name     string        `flag:"-"`
+	Port     int           `default:"8000" usage:"network port to listen on"`
+	APIKey   string        `env:"UPSTREAM_TOKEN" envflag:"sensitive"`
+	Timeout  time.Duration `default:"5s"`
+	LogLevel *slog.LevelVar
+	Level    slog.LevelVar `flag:"other-level" default:"warn"`
+	Addr     netip.Addr
+	Shout    upperText
+	Tags     testList
+	Verbose  bool
+	Ratio    float64 `default:"0.5"`
+	Count    int64
+	Max      uint
+	MaxBig   uint64
+	DB       bindDB
+	internal string
+}
+
+func TestParseStruct(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	cfg := bindConfig{Name: "myapp", Verbose: true}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	err := envflag.ParseStruct(fs, &cfg,
+		[]string{"-port", "9000", "-log-level", "debug", "-tags", "a"},
+		[]string{
+			"MYAPP_TIMEOUT=1m",
+			"UPSTREAM_TOKEN=abc123",
+			"MYAPP_API_KEY=ignored",
+			"MYAPP_ADDR=10.0.0.1",
+			"MYAPP_SHOUT=hey",
+			"MYAPP_DB_URL=postgres://db.example.com/app",
+			"MYAPP_DB_PASSWORD=hunter2",
+			"MYAPP_DB_POOL_SIZE=8",
+			"MYAPP_MAX_BIG=12",
+		},
+		envflag.WithPrefix("myapp"),
+	)
+	is.NoErr(err)
+
+	is.Equal(cfg.Name, "myapp")                                    // flag:"-" is untouched
+	is.Equal(cfg.Port, 9000)                                       // from args
+	is.Equal(cfg.APIKey, "abc123")                                 // env override ignores prefix
+	is.Equal(cfg.Timeout, time.Minute)                             // duration
+	is.Equal(cfg.LogLevel.Level(), slog.LevelDebug)                // nil *LevelVar allocated
+	is.Equal(cfg.Level.Level(), slog.LevelWarn)                    // LevelVar default
+	is.Equal(cfg.Addr, netip.MustParseAddr("10.0.0.1"))            // TextUnmarshaler and TextMarshaler
+	is.Equal(cfg.Shout, upperText("HEY"))                          // only TextUnmarshaler
+	is.Equal([]string(cfg.Tags), []string{"a"})                    // flag.Value
+	is.True(cfg.Verbose)                                           // current value is the default
+	is.Equal(cfg.Ratio, 0.5)                                       // float default
+	is.Equal(cfg.DB.URL.String(), "postgres://db.example.com/app") // nested url.URL
+	is.Equal(cfg.DB.Password, "hunter2")                           // nested
+	is.Equal(cfg.DB.Pool.Size, 8)                                  // nested nil pointer allocated
+	is.Equal(cfg.MaxBig, uint64(12))                               // kebab case name
+
+	is.Equal(fs.Lookup("port").DefValue, "8000")
+	is.Equal(fs.Lookup("port").Usage, "network port to listen on")
+	is.Equal(fs.Lookup("db.pool.size").DefValue, "4")
+	is.True(fs.Lookup("name") == nil)     // skipped field
+	is.True(fs.Lookup("internal") == nil) // unexported field
+
+	buf := &bytes.Buffer{}
+	slog.New(slog.NewTextHandler(buf, nil)).Info("test", slog.Any("flags", envflag.LogFlags(fs, envflag.WithSensitive("api-key", "db.password"))))
+	is.True(!strings.Contains(buf.String(), "hunter2"))
+}
+
+func TestBindRequired(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	cfg := bindConfig{}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	err := envflag.ParseStruct(fs, &cfg, nil, nil, envflag.WithPrefix("myapp"))
+	is.True(err != nil) // db.url is required
+	is.Equal(err.Error(), "required flag -db.url or env var MYAPP_DB_URL is not set")
+}
+
+func TestBindSensitive(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	cfg := bindConfig{}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	opts, err := envflag.Bind(fs, &cfg)
+	is.NoErr(err)
+	is.NoErr(envflag.Parse(fs, []string{"-db.url", "x", "-db.password", "hunter2", "-api-key", "abc123"}, nil, opts...))
+
+	buf := &bytes.Buffer{}
+	slog.New(slog.NewTextHandler(buf, nil)).Info("test", slog.Any("flags", envflag.LogFlags(fs, opts...)))
+	t.Log(buf.String())
+	is.True(!strings.Contains(buf.String(), "hunter2")) // tag marked db.password sensitive
+	is.True(!strings.Contains(buf.String(), "abc123"))  // tag marked api-key sensitive
+}
+
+func TestBindListDefault(t *testing.T) {
+	t.Parallel()
+
+	type config struct {
+		Tags   []string          `default:"a,b"`
+		Ports  []int             `default:"80,443"`
+		Labels map[string]string `default:"env=dev,team=core"`
+	}
+
+	testCases := map[string]struct {
+		args   []string
+		env    []string
+		expect config
+	}{
+		"default": {
+			nil, nil,
+			config{Tags: []string{"a", "b"}, Ports: []int{80, 443}, Labels: map[string]string{"env": "dev", "team": "core"}},
+		},
+		"args replace default": {
+			[]string{"-tags", "x", "-ports", "8080", "-labels", "env=prod"},
+			nil,
+			config{Tags: []string{"x"}, Ports: []int{8080}, Labels: map[string]string{"env": "prod"}},
+		},
+		"env replaces default": {
+			nil,
+			[]string{"TAGS=x,y"},
+			config{Tags: []string{"x", "y"}, Ports: []int{80, 443}, Labels: map[string]string{"env": "dev", "team": "core"}},
+		},
+	}
+
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			cfg := config{}
+			fs := flag.NewFlagSet("test-"+name, flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			is.NoErr(envflag.ParseStruct(fs, &cfg, tc.args, tc.env))
+			is.Equal(cfg, tc.expect)
+			is.Equal(fs.Lookup("tags").DefValue, "a,b")
+		})
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		cfg     any
+		errtext string
+	}{
+		"not a pointer": {
+			bindConfig{},
+			"binding flags: envflag_test.bindConfig is not a pointer to a struct",
+		},
+		"unsupported type": {
+			&struct{ Things map[int]string }{},
+			"binding flags: field Things: unsupported type map[int]string",
+		},
+		"bad default": {
+			&struct {
+				Port int `default:"eighty"`
+			}{},
+			`binding flags: field Port: invalid default "eighty": parse error`,
+		},
+		"bad list default": {
+			&struct {
+				Ports []int `default:"80,http"`
+			}{},
+			`binding flags: field Ports: invalid default "80,http": strconv.ParseInt: parsing "http": invalid syntax`,
+		},
+	}
+
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			_, err := envflag.Bind(flag.NewFlagSet("test", flag.ContinueOnError), tc.cfg)
+			is.True(err != nil) // should fail to bind
+			is.Equal(err.Error(), tc.errtext)
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package envflag
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+)
+
+// Command is a flag set along with its subcommands,
+// for tools run as `myapp [flags] <subcommand> [flags]`.
+type Command struct {
+	// Name is the arg which selects the command, unused for the root.
+	Name    string
+	FlagSet *flag.FlagSet
+	// Options apply to this command only, after those passed to ParseCommand,
+	// such as validations of its own flags.
+	Options     []Option
+	Subcommands []*Command
+}
+
+// ancestor is a parent command's flags once parsed.
+type ancestor struct {
+	fs      *flag.FlagSet
+	origins Provenance
+}
+
+// ParseCommand supplements [Parse] for a tree of commands.
+// Starting from root, each command's flag set is parsed as per Parse,
+// and if the first remaining arg names a subcommand, so is that subcommand's.
+// It returns the last command parsed along with the args that remain.
+//
+// Environment variables for a subcommand are prefixed with its parent's prefix
+// and its name, so given WithPrefix("myapp"), flag `count` of `myapp get` maps to
+// `MYAPP_GET_COUNT`.
+// A flag a subcommand shares with a parent, by having the same name,
+// takes the parent's value unless set by the subcommand's own arg or env var,
+// so `myapp -level debug get` or `MYAPP_LEVEL=debug` also sets `-level` for get.
+//
+// opts apply to every command parsed.
+// A config file, per [WithConfigFile] or [WithConfigFileFlag], is only read for root.
+// [WithStrict] accepts the env vars of every command in the tree.
+// [WithProvenance] covers every command parsed, preferring the subcommand's origin of a shared flag,
+// and [WithReloader] reloads the flags of the returned command.
+func ParseCommand(root *Command, args, environ []string, opts ...Option) (*Command, []string, error) {
+	if root.FlagSet.ErrorHandling() != flag.ContinueOnError {
+		return nil, nil, errors.New("flag set does not have ContinueOnError")
+	}
+	rootOpts := append(append([]Option{}, opts...), root.Options...)
+	prefix := newOptions(root.FlagSet, rootOpts).prefix
+
+	treeKeys := map[string]bool{}
+	if err := root.addKnownKeys(prefix, opts, treeKeys); err != nil {
+		return nil, nil, err
+	}
+
+	origins := Provenance{}
+	ancestors := []ancestor{}
+	cmd := root
+	for {
+		cmdOpts := append(append([]Option{}, opts...), cmd.Options...)
+		cmdOpts = append(cmdOpts, commandOptions(cmd == root, prefix, ancestors, treeKeys))
+		o := newOptions(cmd.FlagSet, cmdOpts)
+		if err := o.parse(cmd.FlagSet, args, environ, cmdOpts); err != nil {
+			if cmd != root {
+				err = fmt.Errorf("%v: %w", cmd.Name, err)
+			}
+			return nil, nil, err
+		}
+		for name, origin := range o.origins {
+			origins[name] = origin
+		}
+		if o.provenance != nil {
+			*o.provenance = origins
+		}
+
+		args = cmd.FlagSet.Args()
+		sub := cmd.subcommand(args)
+		if sub == nil {
+			return cmd, args, nil
+		}
+		ancestors = append([]ancestor{{fs: cmd.FlagSet, origins: o.origins}}, ancestors...)
+		prefix = subcommandPrefix(prefix, sub.Name)
+		args = args[1:]
+		cmd = sub
+	}
+}
+
+// commandOptions applies the tree's state to the options for one of its commands.
+func commandOptions(isRoot bool, prefix string, ancestors []ancestor, treeKeys map[string]bool) Option {
+	return func(o *options) {
+		if !isRoot {
+			o.prefix = prefix
+			o.fsPrefix = false
+			o.configFile = ""
+			o.configFlag = ""
+		}
+		o.ancestors = ancestors
+		o.treeKeys = treeKeys
+	}
+}
+
+// addKnownKeys adds the env keys of c and all its subcommands to known,
+// for strict mode to accept any of them.
+func (c *Command) addKnownKeys(prefix string, opts []Option, known map[string]bool) error {
+	if c.FlagSet == nil {
+		return fmt.Errorf("command %v has no flag set", c.Name)
+	}
+	cmdOpts := append(append([]Option{}, opts...), c.Options...)
+	o := newOptions(c.FlagSet, append(cmdOpts, commandOptions(false, prefix, nil, nil)))
+	o.addKnownKeys(c.FlagSet, known)
+	for _, sub := range c.Subcommands {
+		if err := sub.addKnownKeys(subcommandPrefix(prefix, sub.Name), opts, known); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// subcommand finds the subcommand named by the first of args, if any.
+func (c *Command) subcommand(args []string) *Command {
+	if len(args) == 0 {
+		return nil
+	}
+	for _, sub := range c.Subcommands {
+		if sub.Name == args[0] {
+			return sub
+		}
+	}
+	return nil
+}
+
+// subcommandPrefix nests a subcommand's name under its parent's prefix.
+func subcommandPrefix(prefix, name string) string {
+	if prefix == "" {
+		return prefixName(name)
+	}
+	return prefix + "_" + prefixName(name)
+}
+
+// inherited finds the value of f from the nearest parent command defining it,
+// if the parent's value was set by any source.
+// List flags aren't inherited, as their values don't round trip through String.
+func (o *options) inherited(f *flag.Flag) (string, Origin, bool) {
+	if o.isList(f) {
+		return "", Origin{}, false
+	}
+	for _, a := range o.ancestors {
+		if pf := a.fs.Lookup(f.Name); pf != nil {
+			origin := a.origins[f.Name]
+			return pf.Value.String(), origin, origin.Kind != FromDefault
+		}
+	}
+	return "", Origin{}, false
+}
+
+// inherit sets f to a parent command's value.
+func (o *options) inherit(fs *flag.FlagSet, f *flag.Flag, value string, origin Origin) error {
+	o.record(f.Name, origin)
+	if err := fs.Set(f.Name, value); err != nil {
+		return fmt.Errorf("setting flag %v from parent command: %w", f.Name, err)
+	}
+	return nil
+}
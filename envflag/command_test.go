@@ -0,0 +1,201 @@
+package envflag_test
+
+import (
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"importfromprojectlocally/envflag"
+)
+
+func TestParseCommand(t *testing.T) {
+	t.Parallel()
+
+	type target struct {
+		rootLevel string
+		getLevel  string
+		count     int
+		dest      string
+	}
+
+	testCases := map[string]struct {
+		args   []string
+		env    []string
+		opts   []envflag.Option
+		cmd    string
+		rest   []string
+		expect target
+	}{
+		"root only": {
+			nil,
+			[]string{"MYAPP_LEVEL=warn", "MYAPP_GET_COUNT=3"},
+			nil,
+			"", nil,
+			target{rootLevel: "warn", getLevel: "info"},
+		},
+		"subcommand env prefix": {
+			[]string{"get", "stream"},
+			[]string{"MYAPP_GET_COUNT=3", "MYAPP_COUNT=4", "COUNT=5"},
+			nil,
+			"get", []string{"stream"},
+			target{rootLevel: "info", getLevel: "info", count: 3},
+		},
+		"subcommand arg": {
+			[]string{"get", "-count", "2", "stream"},
+			[]string{"MYAPP_GET_COUNT=3"},
+			nil,
+			"get", []string{"stream"},
+			target{rootLevel: "info", getLevel: "info", count: 2},
+		},
+		"shared from parent env": {
+			[]string{"get"},
+			[]string{"MYAPP_LEVEL=warn"},
+			nil,
+			"get", []string{},
+			target{rootLevel: "warn", getLevel: "warn"},
+		},
+		"shared from own env": {
+			[]string{"get"},
+			[]string{"MYAPP_LEVEL=warn", "MYAPP_GET_LEVEL=error"},
+			nil,
+			"get", []string{},
+			target{rootLevel: "warn", getLevel: "error"},
+		},
+		"shared from parent arg": {
+			[]string{"-level", "debug", "get"},
+			[]string{"MYAPP_GET_LEVEL=error"},
+			nil,
+			"get", []string{},
+			target{rootLevel: "debug", getLevel: "debug"},
+		},
+		"shared from own arg": {
+			[]string{"-level", "debug", "get", "-level", "error"},
+			nil,
+			nil,
+			"get", []string{},
+			target{rootLevel: "debug", getLevel: "error"},
+		},
+		"unknown subcommand": {
+			[]string{"list"},
+			nil,
+			nil,
+			"", []string{"list"},
+			target{rootLevel: "info", getLevel: "info"},
+		},
+		"strict accepts whole tree": {
+			[]string{"get"},
+			[]string{"MYAPP_GET_COUNT=3", "MYAPP_PUT_DEST=x"},
+			[]envflag.Option{envflag.WithStrict()},
+			"get", []string{},
+			target{rootLevel: "info", getLevel: "info", count: 3},
+		},
+		"sibling env ignored": {
+			[]string{"put"},
+			[]string{"MYAPP_GET_COUNT=3", "MYAPP_PUT_DEST=x"},
+			nil,
+			"put", []string{},
+			target{rootLevel: "info", getLevel: "info", dest: "x"},
+		},
+	}
+
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			tgt := target{}
+			rootFS := flag.NewFlagSet("myapp", flag.ContinueOnError)
+			rootFS.SetOutput(io.Discard)
+			rootFS.StringVar(&tgt.rootLevel, "level", "info", "test shared")
+			getFS := flag.NewFlagSet("myapp get", flag.ContinueOnError)
+			getFS.SetOutput(io.Discard)
+			getFS.StringVar(&tgt.getLevel, "level", "info", "test shared")
+			getFS.IntVar(&tgt.count, "count", 0, "test subcommand")
+			putFS := flag.NewFlagSet("myapp put", flag.ContinueOnError)
+			putFS.SetOutput(io.Discard)
+			putFS.StringVar(&tgt.dest, "dest", "", "test sibling")
+			root := &envflag.Command{
+				FlagSet: rootFS,
+				Subcommands: []*envflag.Command{
+					{Name: "get", FlagSet: getFS},
+					{Name: "put", FlagSet: putFS},
+				},
+			}
+			opts := append([]envflag.Option{envflag.WithPrefix("myapp")}, tc.opts...)
+			cmd, rest, err := envflag.ParseCommand(root, tc.args, tc.env, opts...)
+			is.NoErr(err)
+			is.Equal(cmd.Name, tc.cmd)
+			is.Equal(rest, tc.rest)
+			is.Equal(tgt, tc.expect)
+		})
+	}
+}
+
+func TestParseCommandErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		args    []string
+		env     []string
+		errtext string
+	}{
+		"bad subcommand env": {
+			[]string{"get"},
+			[]string{"MYAPP_GET_COUNT=many"},
+			"get: setting flag count from env var MYAPP_GET_COUNT: parse error",
+		},
+		"bad subcommand arg": {
+			[]string{"get", "-nope"},
+			nil,
+			"get: parsing arg flags: flag provided but not defined: -nope",
+		},
+		"strict typo": {
+			[]string{"get"},
+			[]string{"MYAPP_GET_CUONT=3"},
+			"unknown env var MYAPP_GET_CUONT, did you mean MYAPP_GET_COUNT?",
+		},
+	}
+
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			rootFS := flag.NewFlagSet("myapp", flag.ContinueOnError)
+			rootFS.SetOutput(io.Discard)
+			rootFS.String("level", "info", "test shared")
+			getFS := flag.NewFlagSet("myapp get", flag.ContinueOnError)
+			getFS.SetOutput(io.Discard)
+			getFS.Int("count", 0, "test subcommand")
+			root := &envflag.Command{FlagSet: rootFS, Subcommands: []*envflag.Command{{Name: "get", FlagSet: getFS}}}
+			_, _, err := envflag.ParseCommand(root, tc.args, tc.env, envflag.WithPrefix("myapp"), envflag.WithStrict())
+			is.True(err != nil) // should fail
+			is.Equal(err.Error(), tc.errtext)
+		})
+	}
+}
+
+func TestParseCommandProvenance(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	prov := envflag.Provenance{}
+	rootFS := flag.NewFlagSet("myapp", flag.ContinueOnError)
+	rootFS.SetOutput(io.Discard)
+	rootFS.String("level", "info", "test shared")
+	getFS := flag.NewFlagSet("myapp get", flag.ContinueOnError)
+	getFS.SetOutput(io.Discard)
+	getFS.Int("count", 0, "test subcommand")
+	root := &envflag.Command{FlagSet: rootFS, Subcommands: []*envflag.Command{{Name: "get", FlagSet: getFS}}}
+	_, _, err := envflag.ParseCommand(root, []string{"get"}, []string{"MYAPP_LEVEL=warn", "MYAPP_GET_COUNT=3"},
+		envflag.WithPrefix("myapp"), envflag.WithProvenance(&prov))
+	is.NoErr(err)
+	is.Equal(prov, envflag.Provenance{
+		"level": {Kind: envflag.FromEnv, Key: "MYAPP_LEVEL"},
+		"count": {Kind: envflag.FromEnv, Key: "MYAPP_GET_COUNT"},
+	})
+}
@@ -0,0 +1,243 @@
+package envflag
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// entry is a candidate flag value from some source,
+// along with where in that source it was found.
+type entry struct {
+	value string
+	// items holds pre-split values, such as from a JSON array,
+	// which are each set individually rather than split from value.
+	items []string
+	file  string
+	line  int
+}
+
+// location formats the file and line of an entry for error messages.
+func (e entry) location() string {
+	return fmt.Sprintf("%s:%d", e.file, e.line)
+}
+
+// empty reports if the entry supplies no value at all.
+func (e entry) empty() bool {
+	return e.value == "" && len(e.items) == 0
+}
+
+// WithEnvFile reads `.env` style files as an additional source
+// consulted after the process environment,
+// ie, os.Environ() overrides values in any env file.
+// Keys in an env file are environment variable names,
+// so are subject to the same prefixing as the real environment.
+// When several files are given, later files override earlier ones.
+//
+// Each line is `KEY=value`, optionally preceded by `export `.
+// Blank lines and lines starting with # are ignored.
+// Values may be single quoted (taken literally)
+// or double quoted (supporting \n, \t, \", and \\ escapes).
+func WithEnvFile(paths ...string) Option {
+	return func(o *options) {
+		o.envFiles = append(o.envFiles, paths...)
+	}
+}
+
+// WithConfigFile reads a configuration file as the lowest precedence
+// source before flag defaults.
+// Keys in a config file are flag names, not environment variable names.
+//
+// Files ending in `.json` must contain a JSON object;
+// nested objects map to dotted flag names (`{"db":{"url":"..."}}` sets `db.url`),
+// and arrays set the flag once per element.
+// Any other file is read as `name=value` lines,
+// with blank lines and lines starting with # ignored.
+func WithConfigFile(path string) Option {
+	return func(o *options) {
+		o.configFile = path
+	}
+}
+
+// WithConfigFileFlag reads the config file named by the value of flag name,
+// as though it were passed to [WithConfigFile].
+// The flag itself may be set by args or the environment, but not the file;
+// if it is left empty, no config file is read.
+func WithConfigFileFlag(name string) Option {
+	return func(o *options) {
+		o.configFlag = name
+	}
+}
+
+// readEnvFile loads a `.env` style file into a map of environment keys.
+func readEnvFile(path string) (map[string]entry, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("reading env file: %w", err)
+	}
+	defer f.Close()
+
+	entries := map[string]entry{}
+	err = scanLines(f, path, func(e entry, line string) error {
+		line = strings.TrimPrefix(line, "export ")
+		k, v, found := strings.Cut(line, "=")
+		k = strings.TrimSpace(k)
+		if !found || k == "" || strings.ContainsAny(k, " \t") {
+			return fmt.Errorf("%s: expected KEY=value", e.location())
+		}
+		value, err := unquote(strings.TrimSpace(v))
+		if err != nil {
+			return fmt.Errorf("%s: %w", e.location(), err)
+		}
+		e.value = value
+		entries[k] = e
+		return nil
+	})
+	return entries, err
+}
+
+// readConfigFile loads a config file into a map of flag names.
+func readConfigFile(path string) (map[string]entry, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return readJSONConfig(path, data)
+	}
+
+	entries := map[string]entry{}
+	err = scanLines(bytes.NewReader(data), path, func(e entry, line string) error {
+		k, v, found := strings.Cut(line, "=")
+		k = strings.TrimSpace(k)
+		if !found || k == "" {
+			return fmt.Errorf("%s: expected name=value", e.location())
+		}
+		e.value = strings.TrimSpace(v)
+		entries[k] = e
+		return nil
+	})
+	return entries, err
+}
+
+// scanLines calls fn for every line of r that is not blank or a comment,
+// with an entry populated with the file and line number.
+func scanLines(r io.Reader, path string, fn func(e entry, line string) error) error {
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := fn(entry{file: path, line: n}, line); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading %s: %w", path, err)
+	}
+	return nil
+}
+
+// unquote strips matching quotes from a dotenv value.
+// Single quotes are literal, double quotes allow backslash escapes.
+func unquote(v string) (string, error) {
+	if len(v) < 2 || (v[0] != '"' && v[0] != '\'') {
+		return v, nil
+	}
+	if v[len(v)-1] != v[0] {
+		return "", errors.New("unterminated quoted value")
+	}
+	if v[0] == '\'' {
+		return v[1 : len(v)-1], nil
+	}
+	replacer := strings.NewReplacer(`\n`, "\n", `\t`, "\t", `\"`, `"`, `\\`, `\`)
+	return replacer.Replace(v[1 : len(v)-1]), nil
+}
+
+// readJSONConfig walks a JSON object token by token,
+// as decoding to a map would lose the line numbers needed for errors.
+func readJSONConfig(path string, data []byte) (map[string]entry, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	lineAt := func() int { return 1 + bytes.Count(data[:dec.InputOffset()], []byte("\n")) }
+
+	tok, err := dec.Token()
+	if err != nil || tok != json.Delim('{') {
+		return nil, fmt.Errorf("%s:%d: config file must contain a JSON object", path, lineAt())
+	}
+
+	entries := map[string]entry{}
+	var walk func(prefix string) error
+	walk = func(prefix string) error {
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return fmt.Errorf("%s:%d: %w", path, lineAt(), err)
+			}
+			name := prefix + tok.(string) //nolint:forcetypeassert // object keys are always strings
+			e := entry{file: path, line: lineAt()}
+
+			tok, err = dec.Token()
+			if err != nil {
+				return fmt.Errorf("%s: %w", e.location(), err)
+			}
+			switch tok {
+			case json.Delim('{'):
+				if err := walk(name + "."); err != nil {
+					return err
+				}
+				continue
+			case json.Delim('['):
+				e.items = []string{}
+				for dec.More() {
+					if tok, err = dec.Token(); err != nil {
+						return fmt.Errorf("%s: %w", e.location(), err)
+					}
+					s, ok := jsonScalar(tok)
+					if !ok {
+						return fmt.Errorf("%s: %v must be an array of scalar values", e.location(), name)
+					}
+					e.items = append(e.items, s)
+				}
+				if _, err := dec.Token(); err != nil { // consume ]
+					return fmt.Errorf("%s: %w", e.location(), err)
+				}
+			case nil:
+				continue // null leaves the flag alone.
+			default:
+				e.value, _ = jsonScalar(tok)
+			}
+			entries[name] = e
+		}
+		if _, err := dec.Token(); err != nil { // consume }
+			return fmt.Errorf("%s:%d: %w", path, lineAt(), err)
+		}
+		return nil
+	}
+	if err := walk(""); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// jsonScalar renders a non-container JSON token as a flag value.
+func jsonScalar(tok json.Token) (string, bool) {
+	switch v := tok.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	return "", false
+}
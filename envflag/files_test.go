@@ -0,0 +1,226 @@
+package envflag_test
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"importfromprojectlocally/envflag"
+)
+
+// writeFile creates a file in a per-test temporary directory, returning its path.
+func writeFile(t *testing.T, name, contents string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(p, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestParseFilePrecedence(t *testing.T) {
+	t.Parallel()
+
+	type target struct {
+		Host   string
+		Port   int
+		DBURL  string
+		Config string
+	}
+
+	envFile := writeFile(t, ".env", strings.Join([]string{
+		"# comment",
+		"",
+		"export HOST=envfile-host",
+		`DB_URL="postgres://db\tx"`,
+		"PORT='9001'",
+	}, "\n"))
+	laterEnvFile := writeFile(t, "later.env", "PORT=9002\n")
+	configFile := writeFile(t, "app.conf", "host = config-host\nport=9003\ndb.url=config-db\n")
+
+	testCases := map[string]struct {
+		args   []string
+		env    []string
+		opts   []envflag.Option
+		result target
+	}{
+		"defaults": {
+			nil, nil, nil,
+			target{Host: "localhost", Port: 8000},
+		},
+		"config file over default": {
+			nil, nil,
+			[]envflag.Option{envflag.WithConfigFile(configFile)},
+			target{Host: "config-host", Port: 9003, DBURL: "config-db"},
+		},
+		"env file over config file": {
+			nil, nil,
+			[]envflag.Option{envflag.WithConfigFile(configFile), envflag.WithEnvFile(envFile)},
+			target{Host: "envfile-host", Port: 9001, DBURL: "postgres://db\tx"},
+		},
+		"later env file wins": {
+			nil, nil,
+			[]envflag.Option{envflag.WithEnvFile(envFile, laterEnvFile)},
+			target{Host: "envfile-host", Port: 9002, DBURL: "postgres://db\tx"},
+		},
+		"env over env file": {
+			nil,
+			[]string{"HOST=env-host"},
+			[]envflag.Option{envflag.WithConfigFile(configFile), envflag.WithEnvFile(envFile)},
+			target{Host: "env-host", Port: 9001, DBURL: "postgres://db\tx"},
+		},
+		"args over everything": {
+			[]string{"-host", "arg-host"},
+			[]string{"HOST=env-host"},
+			[]envflag.Option{envflag.WithConfigFile(configFile), envflag.WithEnvFile(envFile)},
+			target{Host: "arg-host", Port: 9001, DBURL: "postgres://db\tx"},
+		},
+		"config file flag from args": {
+			[]string{"-config", configFile},
+			nil,
+			[]envflag.Option{envflag.WithConfigFileFlag("config")},
+			target{Host: "config-host", Port: 9003, DBURL: "config-db", Config: configFile},
+		},
+		"config file flag from env": {
+			nil,
+			[]string{"CONFIG=" + configFile, "PORT=1234"},
+			[]envflag.Option{envflag.WithConfigFileFlag("config")},
+			target{Host: "config-host", Port: 1234, DBURL: "config-db", Config: configFile},
+		},
+		"empty config file flag": {
+			nil, nil,
+			[]envflag.Option{envflag.WithConfigFileFlag("config")},
+			target{Host: "localhost", Port: 8000},
+		},
+	}
+
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			tgt := target{}
+			fs := flag.NewFlagSet("test-"+name, flag.ContinueOnError)
+			fs.StringVar(&tgt.Host, "host", "localhost", "test host")
+			fs.IntVar(&tgt.Port, "port", 8000, "test port")
+			fs.StringVar(&tgt.DBURL, "db.url", "", "test nested name")
+			fs.StringVar(&tgt.Config, "config", "", "test config file")
+			fs.SetOutput(io.Discard)
+			is.NoErr(envflag.Parse(fs, tc.args, tc.env, tc.opts...))
+			is.Equal(tgt, tc.result)
+		})
+	}
+}
+
+func TestParseJSONConfig(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	configFile := writeFile(t, "app.json", `{
+  "host": "json-host",
+  "port": 9004,
+  "db": {"url": "json-db"},
+  "tags": ["a", "b,c"],
+  "verbose": true,
+  "config": null
+}`)
+
+	host, port, dbURL, config := "", 0, "", ""
+	tags := []string{}
+	verbose := false
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.StringVar(&host, "host", "localhost", "test host")
+	fs.IntVar(&port, "port", 8000, "test port")
+	fs.StringVar(&dbURL, "db.url", "", "test nested name")
+	fs.StringVar(&config, "config", "", "test config file")
+	fs.SetOutput(io.Discard)
+	fs.Func("tags", "test repeated flag", func(s string) error {
+		tags = append(tags, s)
+		return nil
+	})
+	fs.BoolVar(&verbose, "verbose", false, "test bool")
+
+	is.NoErr(envflag.Parse(fs, nil, nil, envflag.WithConfigFile(configFile)))
+	is.Equal(host, "json-host")
+	is.Equal(port, 9004)
+	is.Equal(dbURL, "json-db")
+	is.Equal(config, "")                 // null leaves it unset
+	is.Equal(tags, []string{"a", "b,c"}) // arrays are not split further
+	is.True(verbose)
+}
+
+func TestParseFileErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		name     string
+		contents string
+		opt      func(string) envflag.Option
+		errtext  string
+	}{
+		"bad env file value": {
+			".env", "HOST=ok\n\nPORT=eighty\n",
+			func(p string) envflag.Option { return envflag.WithEnvFile(p) },
+			".env:3: parse error",
+		},
+		"bad env file line": {
+			".env", "HOST=ok\nnonsense\n",
+			func(p string) envflag.Option { return envflag.WithEnvFile(p) },
+			".env:2: expected KEY=value",
+		},
+		"unterminated quote": {
+			".env", "HOST=\"ok\n",
+			func(p string) envflag.Option { return envflag.WithEnvFile(p) },
+			".env:1: unterminated quoted value",
+		},
+		"bad config value": {
+			"app.conf", "# port\nport=eighty\n",
+			func(p string) envflag.Option { return envflag.WithConfigFile(p) },
+			"app.conf:2: parse error",
+		},
+		"unknown config name": {
+			"app.conf", "host=a\nprot=80\n",
+			func(p string) envflag.Option { return envflag.WithConfigFile(p) },
+			"app.conf:2: unknown flag prot",
+		},
+		"bad json value": {
+			"app.json", "{\n  \"host\": \"a\",\n  \"port\": \"eighty\"\n}",
+			func(p string) envflag.Option { return envflag.WithConfigFile(p) },
+			"app.json:3: parse error",
+		},
+		"json not an object": {
+			"app.json", `["port"]`,
+			func(p string) envflag.Option { return envflag.WithConfigFile(p) },
+			"app.json:1: config file must contain a JSON object",
+		},
+		"missing file": {
+			"exists.conf", "",
+			func(p string) envflag.Option { return envflag.WithConfigFile(p + ".missing") },
+			"exists.conf.missing",
+		},
+	}
+
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			p := writeFile(t, tc.name, tc.contents)
+			fs := flag.NewFlagSet("test-"+name, flag.ContinueOnError)
+			fs.String("host", "localhost", "test host")
+			fs.Int("port", 8000, "test port")
+			fs.SetOutput(io.Discard)
+			err := envflag.Parse(fs, nil, nil, tc.opt(p))
+			is.True(err != nil) // bad file should fail
+			t.Log(err)
+			is.True(strings.Contains(err.Error(), tc.errtext)) // error names file and line
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package envflag
+
+import (
+	"errors"
+	"flag"
+	"strings"
+)
+
+// ListFlag is implemented by any flag.Value which accumulates
+// a value per call to Set, such as a repeatable `-tag a -tag b` flag.
+// Mirroring the IsBoolFlag convention of [flag],
+// environment and file values for a ListFlag which returns true
+// are split into items and Set once per item,
+// rather than Set once with the whole value.
+// [Slice] and [Map] split each value Set themselves, so are passed it whole.
+type ListFlag interface {
+	flag.Value
+	IsListFlag() bool
+}
+
+// WithList marks flag names as lists,
+// for flag.Value types which do not implement [ListFlag],
+// such as those created via [flag.FlagSet.Func].
+func WithList(names ...string) Option {
+	return func(o *options) {
+		for _, name := range names {
+			o.lists[name] = true
+		}
+	}
+}
+
+// WithSeparator changes the separator used to split list values from the default `,`.
+// [Slice] and [Map] always split on `,`, so their args and env values are the same.
+func WithSeparator(sep rune) Option {
+	return func(o *options) {
+		o.separator = sep
+	}
+}
+
+// itemSplitter is implemented by list values which split each value Set into items themselves,
+// such as Slice and Map, so Parse passes them env and file values whole.
+type itemSplitter interface {
+	splitsItems() bool
+}
+
+// splitsItems reports if f splits values itself, rather than needing them split for it.
+func splitsItems(f *flag.Flag) bool {
+	s, ok := f.Value.(itemSplitter)
+	return ok && s.splitsItems()
+}
+
+// isList reports if values for f should be split into items.
+func (o *options) isList(f *flag.Flag) bool {
+	if lf, ok := f.Value.(ListFlag); ok && lf.IsListFlag() {
+		return true
+	}
+	return o.lists[f.Name]
+}
+
+// splitList splits s on sep.
+// Items may be double quoted, or sep may be escaped with a backslash,
+// to include sep in an item, ie `"a,b",c\,d` splits to `a,b` and `c,d`.
+// A backslash also escapes a double quote or another backslash,
+// and is otherwise kept as-is, so Windows paths need no escaping.
+func splitList(s string, sep rune) ([]string, error) {
+	items := []string{}
+	item := strings.Builder{}
+	quoted, escaped := false, false
+	for _, r := range s {
+		switch {
+		case escaped:
+			if r != sep && r != '"' && r != '\\' {
+				item.WriteRune('\\')
+			}
+			item.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+		case r == sep && !quoted:
+			items = append(items, item.String())
+			item.Reset()
+		default:
+			item.WriteRune(r)
+		}
+	}
+	if quoted {
+		return nil, errors.New("unterminated quote in list value")
+	}
+	if escaped {
+		item.WriteRune('\\')
+	}
+	return append(items, item.String()), nil
+}
+
+// quoteListItem quotes item if needed so splitList with the default separator
+// returns it unchanged, for the String of a ListFlag.
+func quoteListItem(item string) string {
+	if !strings.ContainsAny(item, `,"\`) {
+		return item
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(item) + `"`
+}
+
+// joinList is the inverse of splitList with the default separator.
+func joinList(items []string) string {
+	quoted := make([]string, 0, len(items))
+	for _, item := range items {
+		quoted = append(quoted, quoteListItem(item))
+	}
+	return strings.Join(quoted, ",")
+}
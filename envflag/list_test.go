@@ -0,0 +1,115 @@
+package envflag_test
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"importfromprojectlocally/envflag"
+)
+
+// testList is a ListFlag accumulating every value it is Set with.
+type testList []string
+
+func (l *testList) String() string     { return strings.Join(*l, "|") }
+func (l *testList) Set(s string) error { *l = append(*l, s); return nil }
+func (l *testList) IsListFlag() bool   { return true }
+
+func TestParseLists(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		env      string
+		opts     []envflag.Option
+		greeting string
+		tags     []string
+		funcs    []string
+	}{
+		"scalars get the whole value": {
+			"GREETING=hello, world",
+			nil,
+			"hello, world", nil, nil,
+		},
+		"list flag splits": {
+			"TAGS=a,b,c",
+			nil,
+			"", []string{"a", "b", "c"}, nil,
+		},
+		"registered list splits": {
+			"FUNCS=a,b",
+			[]envflag.Option{envflag.WithList("funcs")},
+			"", nil, []string{"a", "b"},
+		},
+		"unregistered func does not split": {
+			"FUNCS=a,b",
+			nil,
+			"", nil, []string{"a,b"},
+		},
+		"quoted items": {
+			`TAGS="a,b",c`,
+			nil,
+			"", []string{"a,b", "c"}, nil,
+		},
+		"escaped separator": {
+			`TAGS=a\,b,c\\,\"d`,
+			nil,
+			"", []string{"a,b", `c\`, `"d`}, nil,
+		},
+		"other backslashes are kept": {
+			`TAGS=C:\dir,D:\`,
+			nil,
+			"", []string{`C:\dir`, `D:\`}, nil,
+		},
+		"custom separator": {
+			"TAGS=a,b;c",
+			[]envflag.Option{envflag.WithSeparator(';')},
+			"", []string{"a,b", "c"}, nil,
+		},
+		"empty items are kept": {
+			"TAGS=a,,b",
+			nil,
+			"", []string{"a", "", "b"}, nil,
+		},
+	}
+
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			greeting := ""
+			var tags testList
+			var funcs []string
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			fs.StringVar(&greeting, "greeting", "", "test scalar")
+			fs.Var(&tags, "tags", "test list")
+			fs.Func("funcs", "test registered list", func(s string) error {
+				funcs = append(funcs, s)
+				return nil
+			})
+
+			is.NoErr(envflag.Parse(fs, nil, []string{tc.env}, tc.opts...))
+			is.Equal(greeting, tc.greeting)
+			is.Equal([]string(tags), tc.tags)
+			is.Equal(funcs, tc.funcs)
+		})
+	}
+}
+
+func TestParseListUnterminatedQuote(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	var tags testList
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&tags, "tags", "test list")
+
+	err := envflag.Parse(fs, nil, []string{`TAGS="a,b`})
+	is.True(err != nil) // quote was not closed
+	is.True(strings.Contains(err.Error(), "env var TAGS"))
+}
@@ -0,0 +1,134 @@
+package envflag
+
+import (
+	"flag"
+	"path/filepath"
+	"strings"
+)
+
+// Option configures optional behaviour of [Parse].
+type Option func(*options)
+
+// options is the accumulated state of every Option passed to Parse.
+type options struct {
+	prefix     string
+	fsPrefix   bool
+	unprefixed bool
+	envFiles   []string
+	configFile string
+	configFlag string
+	provenance *Provenance
+	// origins is always tracked, for validators to check which flags were set,
+	// and is shared with provenance if requested.
+	origins      Provenance
+	lists        map[string]bool
+	separator    rune
+	sensitive    map[string]bool
+	envOverrides map[string]string
+	validators   []validator
+	// secretFileSize is the limit for WithSecretFiles, or 0 if not enabled.
+	secretFileSize int64
+	strict         bool
+	reloadable     map[string]bool
+	reloader       *Reloader
+	// ancestors are the parent commands for ParseCommand, nearest first,
+	// and treeKeys the env keys of every command in the tree.
+	ancestors []ancestor
+	treeKeys  map[string]bool
+}
+
+// newOptions applies opts in order, resolving anything
+// which depends on the flag set being parsed.
+func newOptions(fs *flag.FlagSet, opts []Option) *options {
+	o := &options{
+		origins:      Provenance{},
+		lists:        map[string]bool{},
+		separator:    ',',
+		sensitive:    map[string]bool{},
+		envOverrides: map[string]string{},
+		reloadable:   map[string]bool{},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.fsPrefix && o.prefix == "" {
+		o.prefix = prefixName(filepath.Base(fs.Name()))
+	}
+	return o
+}
+
+// WithPrefix namespaces every environment variable with an application prefix,
+// ie, with a prefix of `myapp`, flag.Name `foo-bar` maps to `MYAPP_FOO_BAR`.
+// The prefix goes through the same mapping as flag names.
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefixName(prefix)
+	}
+}
+
+// WithFlagSetPrefix derives the prefix from the name of the flag set,
+// as passed to [flag.NewFlagSet].
+// Since the skeletons name their flag set after os.Args[0],
+// only the final path element of the name is used,
+// so `/usr/local/bin/my-app` becomes `MY_APP`.
+// An explicit WithPrefix takes precedence.
+func WithFlagSetPrefix() Option {
+	return func(o *options) {
+		o.fsPrefix = true
+	}
+}
+
+// WithUnprefixedFallback also looks up the unprefixed environment variable
+// when the prefixed one is unset or empty,
+// so existing deployments using `FOO_BAR` keep working
+// while moving to `MYAPP_FOO_BAR`.
+func WithUnprefixedFallback() Option {
+	return func(o *options) {
+		o.unprefixed = true
+	}
+}
+
+// record notes the origin of a flag's value.
+func (o *options) record(name string, origin Origin) {
+	o.origins[name] = origin
+}
+
+// envKeys returns the environment variables that may supply flag name,
+// in order of preference.
+func (o *options) envKeys(name string) []string {
+	if key, ok := o.envOverrides[name]; ok {
+		return []string{key}
+	}
+	key := envName(name)
+	if o.prefix == "" {
+		return []string{key}
+	}
+	keys := []string{o.prefix + "_" + key}
+	if o.unprefixed {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// envName maps a flag name to an environment variable name,
+// uppercase with _ separators in place of `[.-/]`.
+func envName(s string) string {
+	// replacer would be a global const if Go allowed such things.
+	// a sync.OnceValue(func replacer() *strings.Replacer {...})
+	// would also work, but sync.OnceValue also can't be a const.
+	// Given the lack of options to have immutable global state,
+	// and the low likelihood this code will be performance load bearing
+	// so just inline it and ignore the issue.
+	replacer := strings.NewReplacer(
+		"-", "_",
+		".", "_",
+		"/", "_",
+	)
+	return replacer.Replace(strings.ToUpper(s))
+}
+
+// prefixName maps a prefix to its environment form,
+// tolerating a trailing separator such as `MYAPP_`.
+func prefixName(s string) string {
+	return strings.TrimRight(envName(s), "_")
+}
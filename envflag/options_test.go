@@ -0,0 +1,93 @@
+package envflag_test
+
+import (
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"importfromprojectlocally/envflag"
+)
+
+func TestParsePrefix(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		fsName string
+		env    []string
+		opts   []envflag.Option
+		port   int
+	}{
+		"no prefix uses bare name": {
+			"myapp",
+			[]string{"PORT=9000", "MYAPP_PORT=9001"},
+			nil,
+			9000,
+		},
+		"prefix": {
+			"myapp",
+			[]string{"PORT=9000", "MYAPP_PORT=9001"},
+			[]envflag.Option{envflag.WithPrefix("myapp")},
+			9001,
+		},
+		"prefix ignores bare name": {
+			"myapp",
+			[]string{"PORT=9000"},
+			[]envflag.Option{envflag.WithPrefix("myapp")},
+			8000,
+		},
+		"prefix trailing separator": {
+			"myapp",
+			[]string{"MY_APP_PORT=9001"},
+			[]envflag.Option{envflag.WithPrefix("my-app_")},
+			9001,
+		},
+		"flag set name prefix": {
+			"/usr/local/bin/my-app",
+			[]string{"PORT=9000", "MY_APP_PORT=9001"},
+			[]envflag.Option{envflag.WithFlagSetPrefix()},
+			9001,
+		},
+		"explicit prefix beats flag set name": {
+			"/usr/local/bin/my-app",
+			[]string{"OTHER_PORT=9002", "MY_APP_PORT=9001"},
+			[]envflag.Option{envflag.WithFlagSetPrefix(), envflag.WithPrefix("other")},
+			9002,
+		},
+		"fallback to unprefixed": {
+			"myapp",
+			[]string{"PORT=9000"},
+			[]envflag.Option{envflag.WithPrefix("myapp"), envflag.WithUnprefixedFallback()},
+			9000,
+		},
+		"prefixed preferred over fallback": {
+			"myapp",
+			[]string{"PORT=9000", "MYAPP_PORT=9001"},
+			[]envflag.Option{envflag.WithPrefix("myapp"), envflag.WithUnprefixedFallback()},
+			9001,
+		},
+		"empty prefixed value falls back": {
+			"myapp",
+			[]string{"PORT=9000", "MYAPP_PORT="},
+			[]envflag.Option{envflag.WithPrefix("myapp"), envflag.WithUnprefixedFallback()},
+			9000,
+		},
+	}
+
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			port := 0
+			fs := flag.NewFlagSet(tc.fsName, flag.ContinueOnError)
+			fs.IntVar(&port, "port", 8000, "test port")
+			fs.SetOutput(io.Discard)
+
+			is.NoErr(envflag.Parse(fs, nil, tc.env, tc.opts...))
+			is.Equal(port, tc.port)
+		})
+	}
+}
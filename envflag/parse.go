@@ -1,10 +1,18 @@
 // Package envflag provides supplemental environment parsing for [flag.FlagSet.Parse]
+//
+// Flags are set from the following sources, in order of precedence:
+//   - command line args
+//   - environment variables, or the files they name per [WithSecretFiles]
+//   - env files, per [WithEnvFile], also following [WithSecretFiles]
+//   - a config file, per [WithConfigFile] or [WithConfigFileFlag]
+//   - the flag's default value
 package envflag
 
 import (
 	"errors"
 	"flag"
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -12,66 +20,267 @@ import (
 // flag names are mapped to env variable names via
 // uppercase with _ separators in place of `[.-/]`
 // ie, flag.Name `foo-bar` maps to environment variable `FOO_BAR`
+// Each value is passed whole to the flag's Set,
+// unless the flag is a list per [ListFlag] or [WithList].
 //
 // fs must have flag.ContinueOnError set,
 // args is expected to be os.Args[1:],
 // and environ is expected to be the return of os.Environ().
-func Parse(fs *flag.FlagSet, args, environ []string) error {
+// Any opts, such as [WithPrefix], are applied in order.
+func Parse(fs *flag.FlagSet, args, environ []string, opts ...Option) error {
 	if fs.ErrorHandling() != flag.ContinueOnError {
 		return errors.New("flag set does not have ContinueOnError")
 	}
+	o := newOptions(fs, opts)
+	if o.provenance != nil {
+		*o.provenance = o.origins
+	}
+	return o.parse(fs, args, environ, opts)
+}
+
+// parse implements Parse with already applied options,
+// keeping opts for any reloader.
+//
+//nolint:gocognit,cyclop // each step depends on the last, so is easiest to follow in one place.
+func (o *options) parse(fs *flag.FlagSet, args, environ []string, opts []Option) error {
 	if err := fs.Parse(args); err != nil {
 		return fmt.Errorf("parsing arg flags: %w", err)
 	}
 
-	// turn os.Environ() format into a useful map.
-	env := map[string]string{}
-	for _, entry := range environ {
-		k, v, found := strings.Cut(entry, "=")
-		if !found {
-			return fmt.Errorf("unexpected environment entry %v", entry)
+	srcs, err := o.envSources(environ)
+	if err != nil {
+		return err
+	}
+	if o.strict {
+		known := o.treeKeys
+		if known == nil {
+			known = o.knownKeys(fs)
+		}
+		if err := o.checkUnknownKeys(known, srcs); err != nil {
+			return err
 		}
-		env[k] = v
 	}
 
-	// replacer would be a global const if Go allowed such things.
-	// a sync.OnceValue(func replacer() *strings.Replacer {...})
-	// would also work, but sync.OnceValue also can't be a const.
-	// Given the lack of options to have immutable global state,
-	// and the low likelihood this code will be performance load bearing
-	// so just inline it and ignore the issue.
-	replacer := strings.NewReplacer(
-		"-", "_",
-		".", "_",
-		"/", "_",
-	)
-	envName := func(s string) string { return replacer.Replace(strings.ToUpper(s)) }
-
 	// args override env, so figure out which flags were manually specified
-	hadArg := map[string]bool{}
+	done := map[string]bool{}
+	argPos := argPositions(args, fs.Args())
 	fs.Visit(func(f *flag.Flag) {
-		hadArg[envName(f.Name)] = true
+		done[f.Name] = true
+		o.record(f.Name, Origin{Kind: FromArg, Arg: argPos[f.Name]})
 	})
 
-	verr := error(nil)
+	// the config file flag has to be resolved before the file it names can be read.
+	configFile := o.configFile
+	if o.configFlag != "" {
+		f := fs.Lookup(o.configFlag)
+		if f == nil {
+			return fmt.Errorf("config file flag %v is not defined", o.configFlag)
+		}
+		if !done[f.Name] {
+			if err := o.setFromSources(fs, f, srcs); err != nil {
+				return err
+			}
+			done[f.Name] = true
+		}
+		if path := f.Value.String(); path != "" {
+			configFile = path
+		}
+	}
+	if configFile != "" {
+		src, err := configSource(fs, configFile)
+		if err != nil {
+			return err
+		}
+		srcs = append(srcs, src)
+	}
+
+	// keep going after a bad value, so every problem is reported at once.
+	errs := []error{}
 	fs.VisitAll(func(f *flag.Flag) {
-		key := envName(f.Name)
-		if verr != nil || hadArg[key] {
-			// on a manual flag or error, skip doing more
+		if done[f.Name] {
+			// on a manual flag, skip doing more
 			return
 		}
+		var err error
+		value, origin, inherited := o.inherited(f)
+		if inherited && origin.Kind == FromArg {
+			// a parent command's arg outranks env, as args always do.
+			err = o.inherit(fs, f, value, origin)
+		} else {
+			err = o.setFromSources(fs, f, srcs)
+			if err == nil && inherited && o.origins[f.Name].Kind == FromDefault {
+				err = o.inherit(fs, f, value, origin)
+			}
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	})
+	errs = append(errs, o.validate(fs))
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
 
-		value := env[key]
-		if value == "" {
-			return
+	if o.reloader != nil {
+		o.reloader.init(fs, opts, environ, configFile, o.origins)
+	}
+	return nil
+}
+
+// envSources reads the environment and any env files,
+// in order of precedence.
+func (o *options) envSources(environ []string) ([]source, error) {
+	env, err := parseEnviron(environ)
+	if err != nil {
+		return nil, err
+	}
+	srcs := []source{{kind: FromEnv, entries: env}}
+
+	// later env files override earlier ones, so consult them last to first.
+	for i := len(o.envFiles) - 1; i >= 0; i-- {
+		entries, err := readEnvFile(o.envFiles[i])
+		if err != nil {
+			return nil, err
 		}
+		srcs = append(srcs, source{kind: FromEnvFile, entries: entries})
+	}
+	return srcs, nil
+}
+
+// configSource reads the config file at path,
+// rejecting any names which are not flags in fs.
+func configSource(fs *flag.FlagSet, path string) (source, error) {
+	entries, err := readConfigFile(path)
+	if err != nil {
+		return source{}, err
+	}
+	if err := checkConfigNames(fs, entries); err != nil {
+		return source{}, err
+	}
+	return source{kind: FromConfigFile, entries: entries, byFlag: true}, nil
+}
+
+// source is a set of candidate flag values,
+// keyed by environment variable name unless byFlag is set.
+type source struct {
+	kind    Kind
+	entries map[string]entry
+	byFlag  bool
+}
 
-		for _, v := range strings.Split(value, ",") {
-			if err := fs.Set(f.Name, v); err != nil {
-				verr = fmt.Errorf("setting flag %v from env var %v: %w", f.Name, key, err)
-				return
+// lookup finds the highest precedence non-empty entry for flag name,
+// returning it along with where it was found, reading it from any secret file.
+func (o *options) lookup(srcs []source, name string) (Origin, entry, bool, error) {
+	origin, e, ok := o.find(srcs, name)
+	if origin.Kind != FromSecretFile {
+		return origin, e, ok, nil
+	}
+	value, err := readSecretFile(origin.Key, origin.File, o.secretFileSize)
+	return origin, entry{value: value}, true, err
+}
+
+// find is as per lookup, but only reports where a secret file is, without reading it.
+func (o *options) find(srcs []source, name string) (Origin, entry, bool) {
+	for _, src := range srcs {
+		keys := []string{name}
+		if !src.byFlag {
+			keys = o.envKeys(name)
+		}
+		for _, k := range keys {
+			if e, ok := src.entries[k]; ok && !e.empty() {
+				return Origin{Kind: src.kind, Key: k, File: e.file, Line: e.line}, e, true
+			}
+			if src.byFlag || o.secretFileSize == 0 {
+				continue
+			}
+			if e, ok := src.entries[k+secretFileSuffix]; ok && !e.empty() {
+				return Origin{Kind: FromSecretFile, Key: k + secretFileSuffix, File: e.value}, entry{}, true
 			}
 		}
-	})
-	return verr
+	}
+	return Origin{Kind: FromDefault}, entry{}, false
+}
+
+// setFromSources sets f from the highest precedence source that has a value for it.
+func (o *options) setFromSources(fs *flag.FlagSet, f *flag.Flag, srcs []source) error {
+	values, where, ok, err := o.sourceValues(f, srcs)
+	if !ok || err != nil {
+		return err
+	}
+	return setValues(fs, f.Name, values, where)
+}
+
+// sourceValues finds the values to Set f to from the highest precedence source,
+// split if f is a list, recording where they were found and describing it for errors.
+func (o *options) sourceValues(f *flag.Flag, srcs []source) ([]string, string, bool, error) {
+	origin, e, ok, err := o.lookup(srcs, f.Name)
+	if err != nil {
+		return nil, "", false, fmt.Errorf("setting flag %v: %w", f.Name, err)
+	}
+	o.record(f.Name, origin)
+	if !ok {
+		return nil, "", false, nil
+	}
+
+	where := "env var " + origin.Key
+	if e.file != "" {
+		where = fmt.Sprintf("%v at %v", origin.Key, e.location())
+	}
+
+	switch {
+	case e.items != nil && splitsItems(f):
+		// joined so the value splits back into the same items.
+		return []string{joinList(e.items)}, where, true, nil
+	case e.items != nil:
+		// already split, such as from a JSON array.
+		return e.items, where, true, nil
+	case o.isList(f) && !splitsItems(f):
+		items, err := splitList(e.value, o.separator)
+		if err != nil {
+			return nil, "", false, fmt.Errorf("setting flag %v from %v: %w", f.Name, where, err)
+		}
+		return items, where, true, nil
+	}
+	return []string{e.value}, where, true, nil
+}
+
+// setValues sets flag name to each of values in turn.
+func setValues(fs *flag.FlagSet, name string, values []string, where string) error {
+	for _, v := range values {
+		if err := fs.Set(name, v); err != nil {
+			return fmt.Errorf("setting flag %v from %v: %w", name, where, err)
+		}
+	}
+	return nil
+}
+
+// parseEnviron turns os.Environ() format into a useful map.
+func parseEnviron(environ []string) (map[string]entry, error) {
+	env := map[string]entry{}
+	for _, kv := range environ {
+		k, v, found := strings.Cut(kv, "=")
+		if !found {
+			return nil, fmt.Errorf("unexpected environment entry %v", kv)
+		}
+		env[k] = entry{value: v}
+	}
+	return env, nil
+}
+
+// checkConfigNames rejects config file entries that do not name a flag,
+// which are almost certainly typos.
+func checkConfigNames(fs *flag.FlagSet, entries map[string]entry) error {
+	unknown := []string{}
+	for name := range entries {
+		if fs.Lookup(name) == nil {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Slice(unknown, func(i, j int) bool { return entries[unknown[i]].line < entries[unknown[j]].line })
+
+	errs := make([]error, 0, len(unknown))
+	for _, name := range unknown {
+		errs = append(errs, fmt.Errorf("%v: unknown flag %v", entries[name].location(), name))
+	}
+	return errors.Join(errs...)
 }
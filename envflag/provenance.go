@@ -0,0 +1,124 @@
+package envflag
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// Kind identifies which source supplied a flag's value.
+type Kind string
+
+// The sources a flag value may come from, per [Parse].
+const (
+	FromDefault    Kind = "default"
+	FromArg        Kind = "arg"
+	FromEnv        Kind = "env"
+	FromEnvFile    Kind = "env-file"
+	FromConfigFile Kind = "config-file"
+	FromSecretFile Kind = "secret-file"
+)
+
+// Origin records where a single flag's value came from.
+type Origin struct {
+	Kind Kind
+	// Key is the environment variable or config file key which was used.
+	Key string
+	// Arg is the index into the args passed to Parse of the flag, if Kind is FromArg.
+	Arg int
+	// File and Line locate the value, if it came from an env or config file.
+	// For a secret file, File is the secret's path and Line is unused.
+	File string
+	Line int
+}
+
+// String formats the origin for humans, ie `env MYAPP_PORT`.
+func (o Origin) String() string {
+	switch o.Kind {
+	case FromArg:
+		return fmt.Sprintf("%v %d", o.Kind, o.Arg)
+	case FromEnv:
+		return fmt.Sprintf("%v %v", o.Kind, o.Key)
+	case FromSecretFile:
+		return fmt.Sprintf("%v %v %v", o.Kind, o.File, o.Key)
+	case FromEnvFile, FromConfigFile:
+		return fmt.Sprintf("%v %v:%d %v", o.Kind, o.File, o.Line, o.Key)
+	case FromDefault:
+	}
+	return string(o.Kind)
+}
+
+// LogValue satisfies slog.LogValuer, rendering only the fields relevant to the Kind.
+func (o Origin) LogValue() slog.Value {
+	attrs := []slog.Attr{slog.String("source", string(o.Kind))}
+	switch o.Kind {
+	case FromArg:
+		attrs = append(attrs, slog.Int("arg", o.Arg))
+	case FromEnv:
+		attrs = append(attrs, slog.String("key", o.Key))
+	case FromSecretFile:
+		attrs = append(attrs, slog.String("key", o.Key), slog.String("file", o.File))
+	case FromEnvFile, FromConfigFile:
+		attrs = append(attrs, slog.String("key", o.Key), slog.String("file", o.File), slog.Int("line", o.Line))
+	case FromDefault:
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// Provenance maps every flag name to the Origin of its value.
+type Provenance map[string]Origin
+
+// LogValue satisfies slog.LogValuer, as a group of flags sorted by name.
+func (p Provenance) LogValue() slog.Value {
+	names := make([]string, 0, len(p))
+	for name := range p {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	attrs := make([]slog.Attr, 0, len(names))
+	for _, name := range names {
+		attrs = append(attrs, slog.Any(name, p[name]))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// WithProvenance fills in p with the Origin of every flag in the flag set
+// once Parse has finished, replacing any previous contents.
+//
+//	prov := envflag.Provenance{}
+//	err := envflag.Parse(fs, args, env, envflag.WithProvenance(&prov))
+func WithProvenance(p *Provenance) Option {
+	return func(o *options) {
+		o.provenance = p
+	}
+}
+
+// LogProvenance logs where each flag got its value from.
+// Useful at startup to answer "where did that setting come from".
+//
+// It takes a logger, rather than using slogext.From(ctx),
+// so envflag has no dependencies outside the standard library
+// and can be copied or symlinked into a project on its own.
+//
+//	envflag.LogProvenance(ctx, slogext.From(ctx), prov)
+func LogProvenance(ctx context.Context, log *slog.Logger, p Provenance) {
+	log.InfoContext(ctx, "configuration sources", slog.Any("flags", p))
+}
+
+// argPositions finds the index in args of each flag name,
+// limited to the args which fs.Parse consumed as flags.
+// Repeated flags report their last position.
+func argPositions(args, remaining []string) map[string]int {
+	pos := map[string]int{}
+	for i, arg := range args[:len(args)-len(remaining)] {
+		if len(arg) < 2 || arg[0] != '-' || arg == "--" {
+			continue
+		}
+		name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		pos[name] = i
+	}
+	return pos
+}
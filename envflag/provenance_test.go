@@ -0,0 +1,99 @@
+package envflag_test
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"importfromprojectlocally/envflag"
+)
+
+func TestProvenance(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	envFile := writeFile(t, ".env", "\nMYAPP_HOST=envfile-host\n")
+	configFile := writeFile(t, "app.conf", "db.url=config-db\n")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("host", "localhost", "test host")
+	fs.Int("port", 8000, "test port")
+	fs.String("db.url", "", "test nested name")
+	fs.String("config", "", "test config file")
+	fs.SetOutput(io.Discard)
+	verbose := false
+	fs.BoolVar(&verbose, "verbose", false, "test bool")
+
+	prov := envflag.Provenance{"stale": {}}
+	err := envflag.Parse(fs,
+		[]string{"-verbose", "--config=" + configFile, "rest", "-port", "1"},
+		[]string{"MYAPP_PORT=9000"},
+		envflag.WithPrefix("myapp"),
+		envflag.WithEnvFile(envFile),
+		envflag.WithConfigFileFlag("config"),
+		envflag.WithProvenance(&prov),
+	)
+	is.NoErr(err)
+
+	is.Equal(prov, envflag.Provenance{
+		"verbose": {Kind: envflag.FromArg, Arg: 0},
+		"config":  {Kind: envflag.FromArg, Arg: 1},
+		"port":    {Kind: envflag.FromEnv, Key: "MYAPP_PORT"}, // -port after "rest" is not a flag
+		"host":    {Kind: envflag.FromEnvFile, Key: "MYAPP_HOST", File: envFile, Line: 2},
+		"db.url":  {Kind: envflag.FromConfigFile, Key: "db.url", File: configFile, Line: 1},
+	})
+}
+
+func TestProvenanceDefault(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("host", "localhost", "test host")
+	fs.Int("port", 8000, "test port")
+	fs.String("db.url", "", "test nested name")
+	fs.String("config", "", "test config file")
+	fs.SetOutput(io.Discard)
+	prov := envflag.Provenance{}
+	is.NoErr(envflag.Parse(fs, nil, []string{"HOST="}, envflag.WithProvenance(&prov)))
+
+	is.Equal(len(prov), 4) // every flag is recorded
+	is.Equal(prov["host"], envflag.Origin{Kind: envflag.FromDefault})
+	is.Equal(prov["host"].String(), "default")
+}
+
+func TestLogProvenance(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	envFile := writeFile(t, ".env", "HOST=envfile-host\n")
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("host", "localhost", "test host")
+	fs.Int("port", 8000, "test port")
+	fs.String("db.url", "", "test nested name")
+	fs.String("config", "", "test config file")
+	fs.SetOutput(io.Discard)
+	prov := envflag.Provenance{}
+	is.NoErr(envflag.Parse(fs, []string{"-config", "x"}, []string{"PORT=9000"},
+		envflag.WithEnvFile(envFile),
+		envflag.WithProvenance(&prov),
+	))
+
+	buf := &bytes.Buffer{}
+	log := slog.New(slog.NewTextHandler(buf, nil))
+	envflag.LogProvenance(context.Background(), log, prov)
+
+	out := buf.String()
+	t.Log(out)
+	is.True(strings.Contains(out, `msg="configuration sources"`))
+	is.True(strings.Contains(out, "flags.config.source=arg flags.config.arg=0"))
+	is.True(strings.Contains(out, "flags.db.url.source=default"))
+	is.True(strings.Contains(out, "flags.host.source=env-file flags.host.key=HOST flags.host.file="+envFile+" flags.host.line=1"))
+	is.True(strings.Contains(out, "flags.port.source=env flags.port.key=PORT"))
+}
@@ -0,0 +1,190 @@
+package envflag
+
+import (
+	"encoding"
+	"flag"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Redacted replaces the value of any sensitive flag or field when logged.
+const Redacted = "[redacted]"
+
+// SensitiveFlag is implemented by any flag.Value which holds a secret,
+// such as an API key, that must not be logged.
+type SensitiveFlag interface {
+	flag.Value
+	IsSensitiveFlag() bool
+}
+
+// WithSensitive marks flag names as holding secrets,
+// for flag.Value types which do not implement [SensitiveFlag].
+func WithSensitive(names ...string) Option {
+	return func(o *options) {
+		for _, name := range names {
+			o.sensitive[name] = true
+		}
+	}
+}
+
+// isSensitive reports if the value of f must be masked.
+func (o *options) isSensitive(f *flag.Flag) bool {
+	if sf, ok := f.Value.(SensitiveFlag); ok && sf.IsSensitiveFlag() {
+		return true
+	}
+	return o.sensitive[f.Name]
+}
+
+// LogFlags renders every flag in fs and its current value as an slog group,
+// with the values of sensitive flags, per [WithSensitive] or [SensitiveFlag],
+// replaced by [Redacted].
+// Empty values are left empty, so it's still apparent if a secret is unset.
+//
+//	log.Info("starting", slog.Any("flags", envflag.LogFlags(fs, envflag.WithSensitive("api-key"))))
+func LogFlags(fs *flag.FlagSet, opts ...Option) slog.LogValuer {
+	return flagsValuer{fs: fs, opts: newOptions(fs, opts)}
+}
+
+type flagsValuer struct {
+	fs   *flag.FlagSet
+	opts *options
+}
+
+// LogValue satisfies slog.LogValuer.
+func (fv flagsValuer) LogValue() slog.Value {
+	attrs := []slog.Attr{}
+	fv.fs.VisitAll(func(f *flag.Flag) {
+		value := f.Value.String()
+		if value != "" && fv.opts.isSensitive(f) {
+			value = Redacted
+		}
+		attrs = append(attrs, slog.String(f.Name, value))
+	})
+	return slog.GroupValue(attrs...)
+}
+
+// LogStruct renders a config struct, or pointer to one, as an slog group,
+// with the values of fields tagged `envflag:"sensitive"`, or of a [SensitiveFlag] type,
+// replaced by [Redacted].
+// As with LogFlags, empty values are left empty.
+//
+// Exported fields are named per their json tag if present, and skipped if it is `-`,
+// so output is consistent with what the JSON handler would have produced.
+// Nested structs are rendered as nested groups.
+//
+//	log.Info("app setup complete", slog.Any("config", envflag.LogStruct(cfg)))
+func LogStruct(v any) slog.LogValuer {
+	return structValuer{v: v}
+}
+
+type structValuer struct {
+	v any
+}
+
+// LogValue satisfies slog.LogValuer.
+func (sv structValuer) LogValue() slog.Value {
+	rv := reflect.ValueOf(sv.v)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return slog.AnyValue(sv.v)
+	}
+	if !rv.CanAddr() {
+		// copy to an addressable value so pointer receiver methods are found.
+		addressable := reflect.New(rv.Type()).Elem()
+		addressable.Set(rv)
+		rv = addressable
+	}
+	return structValue(rv)
+}
+
+// structValue renders an addressable struct as a group.
+func structValue(rv reflect.Value) slog.Value {
+	rt := rv.Type()
+	attrs := make([]slog.Attr, 0, rt.NumField())
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Name
+		if jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ","); jsonName == "-" {
+			continue
+		} else if jsonName != "" {
+			name = jsonName
+		}
+
+		fv := rv.Field(i)
+		if sensitiveField(field, fv) {
+			value := ""
+			if !fv.IsZero() {
+				value = Redacted
+			}
+			attrs = append(attrs, slog.String(name, value))
+			continue
+		}
+		attrs = append(attrs, slog.Attr{Key: name, Value: fieldValue(fv)})
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// sensitiveField reports if the value of an addressable struct field must be masked,
+// as it is tagged `envflag:"sensitive"` or its type is a [SensitiveFlag], as LogFlags would.
+func sensitiveField(field reflect.StructField, fv reflect.Value) bool {
+	if hasTagOption(field, "sensitive") {
+		return true
+	}
+	ptr := fv
+	if fv.Kind() != reflect.Pointer {
+		ptr = fv.Addr()
+	} else if fv.IsNil() {
+		return false
+	}
+	sf, ok := ptr.Interface().(SensitiveFlag)
+	return ok && sf.IsSensitiveFlag()
+}
+
+// fieldValue renders a single addressable struct field,
+// preferring any logging or text representation the type provides.
+func fieldValue(fv reflect.Value) slog.Value {
+	if (fv.Kind() == reflect.Pointer || fv.Kind() == reflect.Interface) && fv.IsNil() {
+		return slog.AnyValue(nil)
+	}
+
+	ptr := fv
+	if fv.Kind() != reflect.Pointer {
+		ptr = fv.Addr()
+	}
+	switch v := ptr.Interface().(type) {
+	case slog.LogValuer:
+		return v.LogValue()
+	case *time.Duration, *time.Time:
+		return slog.AnyValue(ptr.Elem().Interface())
+	case encoding.TextMarshaler:
+		if text, err := v.MarshalText(); err == nil {
+			return slog.StringValue(string(text))
+		}
+	case fmt.Stringer:
+		return slog.StringValue(v.String())
+	}
+
+	if elem := ptr.Elem(); elem.Kind() == reflect.Struct {
+		return structValue(elem)
+	}
+	return slog.AnyValue(ptr.Elem().Interface())
+}
+
+// hasTagOption reports if a field's envflag tag includes opt,
+// ie `envflag:"sensitive"` has the option "sensitive".
+func hasTagOption(field reflect.StructField, opt string) bool {
+	for _, o := range strings.Split(field.Tag.Get("envflag"), ",") {
+		if strings.TrimSpace(o) == opt {
+			return true
+		}
+	}
+	return false
+}
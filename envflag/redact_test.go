@@ -0,0 +1,109 @@
+package envflag_test
+
+import (
+	"bytes"
+	"flag"
+	"log/slog"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+
+	"importfromprojectlocally/envflag"
+)
+
+// testSecret is a SensitiveFlag.
+type testSecret string
+
+func (s *testSecret) String() string        { return string(*s) }
+func (s *testSecret) Set(v string) error    { *s = testSecret(v); return nil }
+func (s *testSecret) IsSensitiveFlag() bool { return true }
+
+// logBoth renders attr through both the text and JSON handlers used in the skeletons.
+func logBoth(attr slog.Attr) (string, string) {
+	textbuf, jsonbuf := &bytes.Buffer{}, &bytes.Buffer{}
+	slog.New(slog.NewTextHandler(textbuf, nil)).Info("test", attr)
+	slog.New(slog.NewJSONHandler(jsonbuf, nil)).Info("test", attr)
+	return textbuf.String(), jsonbuf.String()
+}
+
+func TestLogFlags(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	var token testSecret
+	password, host, unset := "", "", ""
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.StringVar(&password, "db.password", "", "test registered secret")
+	fs.StringVar(&host, "host", "", "test plain value")
+	fs.StringVar(&unset, "unset", "", "test empty secret")
+	fs.Var(&token, "token", "test secret value")
+
+	is.NoErr(envflag.Parse(fs, []string{"-host", "example.com"},
+		[]string{"DB_PASSWORD=hunter2", "TOKEN=abc123"}))
+
+	text, js := logBoth(slog.Any("flags", envflag.LogFlags(fs, envflag.WithSensitive("db.password", "unset"))))
+	t.Log(text, js)
+
+	is.True(!strings.Contains(text+js, "hunter2")) // password leaked
+	is.True(!strings.Contains(text+js, "abc123"))  // token leaked
+	is.True(strings.Contains(text, `flags.db.password=[redacted] flags.host=example.com flags.token=[redacted] flags.unset=""`))
+	is.True(strings.Contains(js, `"flags":{"db.password":"[redacted]","host":"example.com","token":"[redacted]","unset":""}`))
+}
+
+type testDBConfig struct {
+	URL      *url.URL
+	Password string `envflag:"sensitive"`
+}
+
+type testConfig struct {
+	Name     string `json:"name"`
+	APIKey   string `envflag:"required, sensitive"`
+	Empty    string `envflag:"sensitive"`
+	Timeout  time.Duration
+	LogLevel *slog.LevelVar
+	Missing  *slog.LevelVar
+	DB       testDBConfig
+	Token    testSecret
+	Internal string `json:"-"`
+	hidden   string
+}
+
+func TestLogStruct(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	u, err := url.Parse("postgres://db.example.com/app")
+	is.NoErr(err)
+	level := &slog.LevelVar{}
+	level.Set(slog.LevelWarn)
+
+	cfg := testConfig{
+		Name:     "myapp",
+		APIKey:   "abc123",
+		Timeout:  3 * time.Second,
+		LogLevel: level,
+		DB:       testDBConfig{URL: u, Password: "hunter2"},
+		Token:    "tok456",
+		Internal: "skipped",
+		hidden:   "skipped",
+	}
+
+	for _, v := range []any{&cfg, cfg} {
+		text, js := logBoth(slog.Any("config", envflag.LogStruct(v)))
+		t.Log(text, js)
+
+		is.True(!strings.Contains(text+js, "hunter2")) // password leaked
+		is.True(!strings.Contains(text+js, "abc123"))  // api key leaked
+		is.True(!strings.Contains(text+js, "tok456"))  // SensitiveFlag field leaked
+		is.True(!strings.Contains(text+js, "skipped")) // unexported or json:"-" field logged
+		is.True(strings.Contains(text, `config.name=myapp config.APIKey=[redacted] config.Empty="" config.Timeout=3s `+
+			`config.LogLevel=WARN config.Missing=<nil> config.DB.URL=postgres://db.example.com/app config.DB.Password=[redacted] `+
+			`config.Token=[redacted]`))
+		is.True(strings.Contains(js, `"config":{"name":"myapp","APIKey":"[redacted]","Empty":"","Timeout":3000000000,`+
+			`"LogLevel":"WARN","Missing":null,"DB":{"URL":"postgres://db.example.com/app","Password":"[redacted]"},`+
+			`"Token":"[redacted]"}`))
+	}
+}
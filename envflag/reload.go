@@ -0,0 +1,341 @@
+package envflag
+
+import (
+	"context"
+	"encoding"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// WithReloadable marks flag names as safe to change while running,
+// so a [Reloader] will update them.
+// Flags set by a command line arg keep that value, as args can't change.
+// List flags cannot be reloadable, as each Set appends to them,
+// and nor can values a reload can't copy, which are those other than
+// the flag package's own, from fs.TextVar, and this package's Enum, URL and ByteSize.
+func WithReloadable(names ...string) Option {
+	return func(o *options) {
+		for _, name := range names {
+			o.reloadable[name] = true
+		}
+	}
+}
+
+// WithReloader prepares r, once Parse succeeds,
+// to later re-read the env files and config file
+// and update any flags marked by [WithReloadable].
+//
+//	reloader := &envflag.Reloader{}
+//	err := envflag.Parse(fs, args, env, envflag.WithReloadable("log-level"), envflag.WithReloader(reloader))
+//	go reloader.Run(ctx, log, 5*time.Second)
+func WithReloader(r *Reloader) Option {
+	return func(o *options) {
+		o.reloader = r
+	}
+}
+
+// Reloader re-applies the sources of a flag set after [Parse],
+// updating only the flags marked by [WithReloadable].
+// A reload is all or nothing: every value is parsed into a copy of its flag
+// and validated before any flag is Set, so a rejected reload changes nothing.
+//
+// A reloaded flag is changed by its Set, so its Value must be safe to Set
+// while being read, such as slog.LevelVar,
+// or only be read by functions passed to Subscribe.
+//
+// The zero value is ready to be passed to [WithReloader].
+// A Reloader must not be copied after first use.
+type Reloader struct {
+	mu          sync.Mutex
+	fs          *flag.FlagSet
+	opts        []Option
+	environ     []string
+	configFile  string
+	files       []string
+	origins     Provenance
+	subscribers []func(changed []string)
+}
+
+// init records everything Parse resolved which a reload needs.
+func (r *Reloader) init(fs *flag.FlagSet, opts []Option, environ []string, configFile string, origins Provenance) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	o := newOptions(fs, opts)
+	r.fs = fs
+	r.opts = opts
+	r.environ = environ
+	r.configFile = configFile
+	r.files = append([]string{}, o.envFiles...)
+	if configFile != "" {
+		r.files = append(r.files, configFile)
+	}
+	r.origins = Provenance{}
+	for name, origin := range origins {
+		r.origins[name] = origin
+	}
+}
+
+// Subscribe calls fn with the names of the flags which changed
+// after every successful reload that changed any.
+func (r *Reloader) Subscribe(fn func(changed []string)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscribers = append(r.subscribers, fn)
+}
+
+// Provenance returns the Origin of every flag as of the last successful reload,
+// or of Parse if there hasn't been one.
+func (r *Reloader) Provenance() Provenance {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	p := make(Provenance, len(r.origins))
+	for name, origin := range r.origins {
+		p[name] = origin
+	}
+	return p
+}
+
+// Reload re-reads the env files and config file, with the environment from Parse,
+// and updates every reloadable flag that was not set by an arg.
+// A reloadable flag no longer set by any source is reset to its default.
+// It returns the names of the flags that changed, in lexical order.
+func (r *Reloader) Reload() ([]string, error) {
+	changed, subscribers, err := r.reload()
+	if err != nil {
+		return nil, fmt.Errorf("reloading flags: %w", err)
+	}
+	if len(changed) > 0 {
+		for _, fn := range subscribers {
+			fn(changed)
+		}
+	}
+	return changed, nil
+}
+
+// reload applies the sources under the lock,
+// returning the subscribers to notify once it's released.
+func (r *Reloader) reload() ([]string, []func([]string), error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.fs == nil {
+		return nil, nil, errors.New("reloader was not passed to a successful Parse")
+	}
+
+	o := newOptions(r.fs, r.opts)
+	srcs, err := o.envSources(r.environ)
+	if err != nil {
+		return nil, nil, err
+	}
+	if r.configFile != "" {
+		src, err := configSource(r.fs, r.configFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		srcs = append(srcs, src)
+	}
+	// validators see the reloaded origins along with those from Parse.
+	for name, origin := range r.origins {
+		o.origins[name] = origin
+	}
+
+	// every new value is parsed into a copy of its flag's value, in a staged flag set,
+	// so nothing live is Set until the whole reload has been validated.
+	staged := flag.NewFlagSet(r.fs.Name(), flag.ContinueOnError)
+	staged.SetOutput(io.Discard)
+	pending := map[string][]string{}
+	errs := []error{}
+	r.fs.VisitAll(func(f *flag.Flag) {
+		value := f.Value
+		if o.reloadable[f.Name] && r.origins[f.Name].Kind != FromArg {
+			copied, values, err := o.stage(f, srcs)
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			if copied.String() != f.Value.String() {
+				pending[f.Name] = values
+			}
+			value = copied
+		}
+		staged.Var(value, f.Name, f.Usage)
+		staged.Lookup(f.Name).DefValue = f.DefValue
+	})
+	if err := errors.Join(errs...); err != nil {
+		return nil, nil, err
+	}
+	if err := o.validate(staged); err != nil {
+		return nil, nil, err
+	}
+
+	// each value already parsed into a copy of the same type, so only a broken Value fails here.
+	changed := []string{}
+	r.fs.VisitAll(func(f *flag.Flag) {
+		if values, ok := pending[f.Name]; ok {
+			changed = append(changed, f.Name)
+			errs = append(errs, setValues(r.fs, f.Name, values, "reload"))
+		}
+	})
+	r.origins = o.origins
+	return changed, append([]func([]string){}, r.subscribers...), errors.Join(errs...)
+}
+
+// stage parses the reloaded value of f into a copy of its value,
+// returning the copy and the values to later Set f to.
+// A flag no longer set by any source is reset to its default.
+func (o *options) stage(f *flag.Flag, srcs []source) (flag.Value, []string, error) {
+	if o.isList(f) {
+		return nil, nil, fmt.Errorf("flag %v: list flags cannot be reloaded", f.Name)
+	}
+	copied, ok := stageValue(f.Value)
+	if !ok {
+		return nil, nil, fmt.Errorf("flag %v: %T values cannot be reloaded", f.Name, f.Value)
+	}
+	values, where, ok, err := o.sourceValues(f, srcs)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		values, where = []string{f.DefValue}, "default"
+	}
+	for _, v := range values {
+		if err := copied.Set(v); err != nil {
+			return nil, nil, fmt.Errorf("setting flag %v from %v: %w", f.Name, where, err)
+		}
+	}
+	return copied, values, nil
+}
+
+// stageValue returns a copy of v which can be Set without changing v,
+// or false if v is not a type a reload supports.
+func stageValue(v flag.Value) (flag.Value, bool) {
+	switch v := v.(type) {
+	case *Enum:
+		value := *v.value
+		return &Enum{value: &value, allowed: v.allowed}, true
+	case *URL:
+		u := *v.url
+		return &URL{url: &u, schemes: v.schemes}, true
+	case flag.Getter:
+		// as from fs.TextVar.
+		if p, ok := v.Get().(encoding.TextUnmarshaler); ok {
+			copied, ok := copyPointer(p)
+			return &stagedText{copied}, ok
+		}
+	}
+	copied, ok := copyPointer(v)
+	if !ok || !isScalar(reflect.TypeOf(v).Elem().Kind()) {
+		return nil, false
+	}
+	return copied, true
+}
+
+// copyPointer returns a pointer to a shallow copy of what p points to.
+func copyPointer[T any](p T) (T, bool) {
+	rv := reflect.ValueOf(p)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return p, false
+	}
+	copied := reflect.New(rv.Elem().Type())
+	copied.Elem().Set(rv.Elem())
+	return copied.Interface().(T), true //nolint:forcetypeassert // the same type as p.
+}
+
+// isScalar reports if k holds its whole value, so a shallow copy is independent,
+// as for the flag package's own types and the likes of [ByteSize].
+func isScalar(k reflect.Kind) bool {
+	return k == reflect.String || (k >= reflect.Bool && k <= reflect.Complex128)
+}
+
+// stagedText is a flag.Value like the one from fs.TextVar.
+type stagedText struct {
+	p encoding.TextUnmarshaler
+}
+
+// Set satisfies flag.Value.
+func (t *stagedText) Set(s string) error {
+	return t.p.UnmarshalText([]byte(s))
+}
+
+// String satisfies flag.Value.
+func (t *stagedText) String() string {
+	if m, ok := t.p.(encoding.TextMarshaler); ok {
+		if text, err := m.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+	return ""
+}
+
+// Get satisfies flag.Getter.
+func (t *stagedText) Get() any {
+	return t.p
+}
+
+// Run reloads on SIGHUP, and when any env file or the config file changes,
+// until ctx is done.
+// Files are checked for a changed size or modification time every poll interval,
+// or never if poll is not positive.
+// Changes are logged to log, as are rejected reloads,
+// which leave the previous values in place.
+func (r *Reloader) Run(ctx context.Context, log *slog.Logger, poll time.Duration) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var tick <-chan time.Time
+	if poll > 0 {
+		ticker := time.NewTicker(poll)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	r.mu.Lock()
+	files := r.files
+	r.mu.Unlock()
+	stats := statFiles(files)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			log.InfoContext(ctx, "reloading configuration", slog.String("reason", "SIGHUP"))
+		case <-tick:
+			latest := statFiles(files)
+			if latest == stats {
+				continue
+			}
+			stats = latest
+			log.InfoContext(ctx, "reloading configuration", slog.String("reason", "file changed"))
+		}
+
+		changed, err := r.Reload()
+		if err != nil {
+			log.ErrorContext(ctx, "rejected configuration reload", slog.Any("error", err))
+			continue
+		}
+		log.InfoContext(ctx, "reloaded configuration", slog.Any("changed", changed))
+	}
+}
+
+// statFiles summarises the size and modification time of every file,
+// treating a missing file as empty, so that any change is a different string.
+func statFiles(paths []string) string {
+	stats := ""
+	for _, path := range paths {
+		if fi, err := os.Stat(path); err == nil {
+			stats += fmt.Sprintf("%v:%d:%d\n", path, fi.Size(), fi.ModTime().UnixNano())
+		}
+	}
+	return stats
+}
@@ -0,0 +1,223 @@
+package envflag_test
+
+import (
+	"context"
+	"flag"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+
+	"importfromprojectlocally/envflag"
+)
+
+func reloadOpts(path string, r *envflag.Reloader) []envflag.Option {
+	return []envflag.Option{
+		envflag.WithEnvFile(path),
+		envflag.WithReloadable("level", "workers", "name"),
+		envflag.Range("workers", 1, 10),
+		envflag.WithReloader(r),
+	}
+}
+
+func TestReload(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	path := writeFile(t, ".env", "LEVEL=debug\nWORKERS=2\nPORT=1\n")
+	level, workers, name, port := slog.LevelVar{}, 0, "", 0
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.TextVar(&level, "level", &level, "test reloadable")
+	fs.IntVar(&workers, "workers", 1, "test reloadable validated")
+	fs.StringVar(&name, "name", "default", "test reloadable set by arg")
+	fs.IntVar(&port, "port", 8000, "test not reloadable")
+	r := &envflag.Reloader{}
+	is.NoErr(envflag.Parse(fs, []string{"-name", "arg"}, nil, reloadOpts(path, r)...))
+	is.Equal(level.Level(), slog.LevelDebug)
+
+	notified := [][]string{}
+	r.Subscribe(func(changed []string) { notified = append(notified, changed) })
+
+	is.NoErr(os.WriteFile(path, []byte("LEVEL=warn\nWORKERS=2\nPORT=2\nNAME=env\n"), 0o600))
+	changed, err := r.Reload()
+	is.NoErr(err)
+	is.Equal(changed, []string{"level"})
+	is.Equal(notified, [][]string{{"level"}})
+	is.Equal(level.Level(), slog.LevelWarn)
+	is.Equal(workers, 2)  // unchanged value
+	is.Equal(port, 1)     // not reloadable
+	is.Equal(name, "arg") // args always win
+	is.Equal(r.Provenance()["level"].Line, 1)
+
+	// a value no longer set anywhere reverts to its default.
+	is.NoErr(os.WriteFile(path, []byte("WORKERS=3\n"), 0o600))
+	changed, err = r.Reload()
+	is.NoErr(err)
+	is.Equal(changed, []string{"level", "workers"})
+	is.Equal(level.Level(), slog.LevelInfo)
+	is.Equal(workers, 3)
+	is.Equal(r.Provenance()["level"].Kind, envflag.FromDefault)
+
+	// nothing changed, so no one is notified.
+	changed, err = r.Reload()
+	is.NoErr(err)
+	is.Equal(len(changed), 0)
+	is.Equal(len(notified), 2)
+}
+
+// recordingLevel is a slog.LevelVar which records every copy of itself that's Set.
+type recordingLevel struct {
+	slog.LevelVar
+	sets *[]*recordingLevel
+}
+
+func (l *recordingLevel) UnmarshalText(text []byte) error {
+	*l.sets = append(*l.sets, l)
+	return l.LevelVar.UnmarshalText(text)
+}
+
+func TestReloadRejected(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		contents string
+		errtext  string
+	}{
+		"invalid value": {
+			"LEVEL=error\nWORKERS=many\n",
+			".env:2: parse error",
+		},
+		"fails validation": {
+			"LEVEL=error\nWORKERS=99\n",
+			"reloading flags: flag -workers: 99 is not between 1 and 10",
+		},
+		"unreadable file": {
+			"LEVEL=error\nWORKERS\n",
+			".env:2: expected KEY=value",
+		},
+	}
+
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			path := writeFile(t, ".env", "LEVEL=debug\nWORKERS=2\n")
+			sets := []*recordingLevel{}
+			level, workers := recordingLevel{sets: &sets}, 0
+			fs := flag.NewFlagSet("test-"+name, flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			fs.TextVar(&level, "level", &level, "test reloadable")
+			fs.IntVar(&workers, "workers", 1, "test reloadable validated")
+			r := &envflag.Reloader{}
+			is.NoErr(envflag.Parse(fs, nil, nil, reloadOpts(path, r)...))
+			r.Subscribe(func([]string) { t.Error("subscriber called on a rejected reload") })
+
+			sets = sets[:0]
+			is.NoErr(os.WriteFile(path, []byte(tc.contents), 0o600))
+			_, err := r.Reload()
+			is.True(err != nil)                                // should reject the reload
+			is.True(strings.Contains(err.Error(), tc.errtext)) // error names the problem
+
+			// every value is left as it was.
+			is.Equal(level.Level(), slog.LevelDebug)
+			is.Equal(workers, 2)
+			is.Equal(r.Provenance()["level"].Line, 1)
+			for _, set := range sets {
+				is.True(set != &level) // live flag should never be Set
+			}
+		})
+	}
+}
+
+func TestReloadNotParsed(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	_, err := (&envflag.Reloader{}).Reload()
+	is.Equal(err.Error(), "reloading flags: reloader was not passed to a successful Parse")
+}
+
+func TestReloadUnsupported(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		define  func(fs *flag.FlagSet)
+		opts    []envflag.Option
+		errtext string
+	}{
+		"list": {
+			func(fs *flag.FlagSet) { fs.String("name", "default", "test list") },
+			[]envflag.Option{envflag.WithList("name")},
+			"reloading flags: flag name: list flags cannot be reloaded",
+		},
+		"func": {
+			func(fs *flag.FlagSet) { fs.Func("name", "test func", func(string) error { return nil }) },
+			nil,
+			"reloading flags: flag name: flag.funcValue values cannot be reloaded",
+		},
+	}
+
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			fs := flag.NewFlagSet("test-"+name, flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			tc.define(fs)
+			r := &envflag.Reloader{}
+			opts := append([]envflag.Option{envflag.WithReloadable("name"), envflag.WithReloader(r)}, tc.opts...)
+			is.NoErr(envflag.Parse(fs, nil, nil, opts...))
+
+			_, err := r.Reload()
+			is.Equal(err.Error(), tc.errtext)
+		})
+	}
+}
+
+func TestReloaderRun(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	path := writeFile(t, ".env", "LEVEL=debug\n")
+	level := slog.LevelVar{}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.TextVar(&level, "level", &level, "test reloadable")
+	fs.Int("workers", 1, "test reloadable validated")
+	fs.String("name", "default", "test reloadable set by arg")
+	r := &envflag.Reloader{}
+	is.NoErr(envflag.Parse(fs, nil, nil, reloadOpts(path, r)...))
+
+	notified := make(chan []string, 1)
+	r.Subscribe(func(changed []string) { notified <- changed })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.Run(ctx, slog.New(slog.NewTextHandler(io.Discard, nil)), time.Millisecond)
+	}()
+	// give Run time to note the file's initial state.
+	time.Sleep(20 * time.Millisecond)
+
+	is.NoErr(os.WriteFile(path, []byte("LEVEL=error\n"), 0o600))
+	select {
+	case changed := <-notified:
+		is.Equal(changed, []string{"level"})
+	case <-time.After(5 * time.Second):
+		t.Fatal("file change was not reloaded")
+	}
+	is.Equal(level.Level(), slog.LevelError)
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,57 @@
+package envflag
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultSecretFileSize is the largest secret file read by [WithSecretFiles]
+// when no other limit is given.
+// Secrets are keys and passwords, not data,
+// so anything bigger is likely a mistaken path.
+const DefaultSecretFileSize = 64 * 1024
+
+// secretFileSuffix is appended to an environment key to name a file holding its value.
+const secretFileSuffix = "_FILE"
+
+// WithSecretFiles follows the Docker and Kubernetes secrets convention:
+// if `FOO` is unset or empty but `FOO_FILE` is set,
+// then the contents of the file `FOO_FILE` names is used as the value for `FOO`,
+// minus a single trailing newline.
+// This applies to both the environment and env files,
+// and uses the same prefixes, ie `MYAPP_API_KEY_FILE`.
+//
+// Files larger than maxSize bytes are rejected with an error;
+// if maxSize is <= 0, [DefaultSecretFileSize] is used.
+func WithSecretFiles(maxSize int64) Option {
+	if maxSize <= 0 {
+		maxSize = DefaultSecretFileSize
+	}
+	return func(o *options) {
+		o.secretFileSize = maxSize
+	}
+}
+
+// readSecretFile reads the value from a secret file for env var key.
+func readSecretFile(key, path string, maxSize int64) (string, error) {
+	f, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return "", fmt.Errorf("reading secret file for %v: %w", key, err)
+	}
+	defer f.Close()
+
+	// read one extra byte to detect an oversized file without reading all of it.
+	data, err := io.ReadAll(io.LimitReader(f, maxSize+1))
+	if err != nil {
+		return "", fmt.Errorf("reading secret file for %v: %w", key, err)
+	}
+	if int64(len(data)) > maxSize {
+		return "", fmt.Errorf("secret file %v for %v is larger than %d bytes", path, key, maxSize)
+	}
+
+	value := strings.TrimSuffix(string(data), "\n")
+	return strings.TrimSuffix(value, "\r"), nil
+}
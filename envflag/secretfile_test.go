@@ -0,0 +1,128 @@
+package envflag_test
+
+import (
+	"flag"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"importfromprojectlocally/envflag"
+)
+
+func TestParseSecretFiles(t *testing.T) {
+	t.Parallel()
+
+	secret := writeFile(t, "secret", "hunter2\n")
+	crlf := writeFile(t, "crlf", "hunter2\r\n")
+	multi := writeFile(t, "multi", "line1\nline2\n\n")
+	big := writeFile(t, "big", strings.Repeat("x", 17))
+
+	testCases := map[string]struct {
+		args    []string
+		env     []string
+		opts    []envflag.Option
+		result  string
+		errtext string
+	}{
+		"not enabled": {
+			nil,
+			[]string{"API_KEY_FILE=" + secret},
+			nil,
+			"", "",
+		},
+		"reads file": {
+			nil,
+			[]string{"API_KEY_FILE=" + secret},
+			[]envflag.Option{envflag.WithSecretFiles(0)},
+			"hunter2", "",
+		},
+		"trims only one newline": {
+			nil,
+			[]string{"API_KEY_FILE=" + multi},
+			[]envflag.Option{envflag.WithSecretFiles(0)},
+			"line1\nline2\n", "",
+		},
+		"trims crlf": {
+			nil,
+			[]string{"API_KEY_FILE=" + crlf},
+			[]envflag.Option{envflag.WithSecretFiles(0)},
+			"hunter2", "",
+		},
+		"plain var wins": {
+			nil,
+			[]string{"API_KEY=plain", "API_KEY_FILE=" + secret},
+			[]envflag.Option{envflag.WithSecretFiles(0)},
+			"plain", "",
+		},
+		"arg wins": {
+			[]string{"-api-key", "arg"},
+			[]string{"API_KEY_FILE=" + secret},
+			[]envflag.Option{envflag.WithSecretFiles(0)},
+			"arg", "",
+		},
+		"prefixed": {
+			nil,
+			[]string{"MYAPP_API_KEY_FILE=" + secret, "API_KEY=plain"},
+			[]envflag.Option{envflag.WithSecretFiles(0), envflag.WithPrefix("myapp"), envflag.WithUnprefixedFallback()},
+			"hunter2", "",
+		},
+		"size limit": {
+			nil,
+			[]string{"API_KEY_FILE=" + big},
+			[]envflag.Option{envflag.WithSecretFiles(16)},
+			"", "is larger than 16 bytes",
+		},
+		"missing file": {
+			nil,
+			[]string{"API_KEY_FILE=/nonexistent/secret"},
+			[]envflag.Option{envflag.WithSecretFiles(0)},
+			"", "setting flag api-key: reading secret file for API_KEY_FILE: open /nonexistent/secret",
+		},
+	}
+
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			apiKey := ""
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			fs.StringVar(&apiKey, "api-key", "", "test secret")
+
+			err := envflag.Parse(fs, tc.args, tc.env, tc.opts...)
+			if tc.errtext != "" {
+				is.True(err != nil) // expected an error
+				t.Log(err)
+				is.True(strings.Contains(err.Error(), tc.errtext))
+				return
+			}
+			is.NoErr(err)
+			is.Equal(apiKey, tc.result)
+		})
+	}
+}
+
+func TestSecretFileProvenance(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	secret := writeFile(t, "secret", "hunter2\n")
+	envFile := writeFile(t, ".env", "API_KEY_FILE="+secret+"\n")
+
+	apiKey := ""
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.StringVar(&apiKey, "api-key", "", "test secret")
+
+	prov := envflag.Provenance{}
+	is.NoErr(envflag.Parse(fs, nil, nil,
+		envflag.WithEnvFile(envFile),
+		envflag.WithSecretFiles(0),
+		envflag.WithProvenance(&prov),
+	))
+	is.Equal(apiKey, "hunter2") // env files also follow _FILE
+	is.Equal(prov["api-key"], envflag.Origin{Kind: envflag.FromSecretFile, Key: "API_KEY_FILE", File: secret})
+}
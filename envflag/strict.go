@@ -0,0 +1,124 @@
+package envflag
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// WithStrict makes Parse return an error for any environment variable,
+// in the environment or an env file, which has the prefix from [WithPrefix]
+// or [WithFlagSetPrefix] but does not match any flag,
+// so a typo like `MYAPP_PROT=9000` is caught rather than silently ignored.
+// Each unknown key is reported along with the most similar valid key, if any.
+//
+// Strict mode requires a prefix, as otherwise every variable in the environment
+// would be a candidate.
+func WithStrict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// knownKeys is every environment variable which may supply a flag in fs.
+func (o *options) knownKeys(fs *flag.FlagSet) map[string]bool {
+	known := map[string]bool{}
+	o.addKnownKeys(fs, known)
+	return known
+}
+
+// addKnownKeys adds the environment variables for fs to known.
+func (o *options) addKnownKeys(fs *flag.FlagSet, known map[string]bool) {
+	fs.VisitAll(func(f *flag.Flag) {
+		for _, k := range o.envKeys(f.Name) {
+			known[k] = true
+			if o.secretFileSize != 0 {
+				known[k+secretFileSuffix] = true
+			}
+		}
+	})
+}
+
+// checkUnknownKeys implements WithStrict over the env keyed sources,
+// given the keys which are known.
+func (o *options) checkUnknownKeys(known map[string]bool, srcs []source) error {
+	if o.prefix == "" {
+		return errors.New("strict mode requires a prefix")
+	}
+
+	candidates := make([]string, 0, len(known))
+	for k := range known {
+		if strings.HasPrefix(k, o.prefix+"_") {
+			candidates = append(candidates, k)
+		}
+	}
+	sort.Strings(candidates)
+
+	errs := []error{}
+	for _, src := range srcs {
+		if src.byFlag {
+			continue
+		}
+		unknown := []string{}
+		for k := range src.entries {
+			if strings.HasPrefix(k, o.prefix+"_") && !known[k] {
+				unknown = append(unknown, k)
+			}
+		}
+		sort.Strings(unknown)
+
+		for _, k := range unknown {
+			msg := "unknown env var " + k
+			if e := src.entries[k]; e.file != "" {
+				msg += " at " + e.location()
+			}
+			if suggestion := closest(k, candidates); suggestion != "" {
+				msg += fmt.Sprintf(", did you mean %v?", suggestion)
+			}
+			errs = append(errs, errors.New(msg))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// closest returns the candidate with the smallest edit distance to s,
+// or empty if none are close enough to plausibly be a typo.
+func closest(s string, candidates []string) string {
+	best, bestDist := "", len(s)/3+1
+	for _, c := range candidates {
+		if d := editDistance(s, c); d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b,
+// counting a transposed pair of characters as a single edit
+// since that is the most common typo.
+func editDistance(a, b string) int {
+	// rows two back, previous, and current of the full distance matrix.
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
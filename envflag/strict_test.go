@@ -0,0 +1,89 @@
+package envflag_test
+
+import (
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"importfromprojectlocally/envflag"
+)
+
+func TestParseStrict(t *testing.T) {
+	t.Parallel()
+
+	envFile := writeFile(t, ".env", "MYAPP_HOST=ok\nMYAPP_HSOT=typo\n")
+	portFile := writeFile(t, "port", "9000\n")
+
+	testCases := map[string]struct {
+		env     []string
+		opts    []envflag.Option
+		errtext string
+	}{
+		"all known": {
+			[]string{"MYAPP_PORT=1", "MYAPP_LOG_LEVEL=debug", "OTHER_PROT=1", "PROT=1"},
+			nil,
+			"",
+		},
+		"typo": {
+			[]string{"MYAPP_PROT=9000"},
+			nil,
+			"unknown env var MYAPP_PROT, did you mean MYAPP_PORT?",
+		},
+		"several unknown sorted with suggestions": {
+			[]string{"MYAPP_ZZZ_UNRELATED=1", "MYAPP_LOG_LEVLE=debug", "MYAPP_PORTT=1"},
+			nil,
+			"unknown env var MYAPP_LOG_LEVLE, did you mean MYAPP_LOG_LEVEL?\n" +
+				"unknown env var MYAPP_PORTT, did you mean MYAPP_PORT?\n" +
+				"unknown env var MYAPP_ZZZ_UNRELATED",
+		},
+		"secret files are known when enabled": {
+			[]string{"MYAPP_PORT_FILE=" + portFile},
+			[]envflag.Option{envflag.WithSecretFiles(0)},
+			"",
+		},
+		"secret files are unknown when disabled": {
+			[]string{"MYAPP_PORT_FILE=" + portFile},
+			nil,
+			"unknown env var MYAPP_PORT_FILE, did you mean MYAPP_PORT?",
+		},
+		"env files are checked": {
+			nil,
+			[]envflag.Option{envflag.WithEnvFile(envFile)},
+			"unknown env var MYAPP_HSOT at " + envFile + ":2, did you mean MYAPP_HOST?",
+		},
+	}
+
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			fs.Int("port", 8000, "test port")
+			fs.String("host", "", "test host")
+			fs.String("log-level", "info", "test level")
+
+			opts := append([]envflag.Option{envflag.WithPrefix("myapp"), envflag.WithStrict()}, tc.opts...)
+			err := envflag.Parse(fs, nil, tc.env, opts...)
+			if tc.errtext == "" {
+				is.NoErr(err)
+				return
+			}
+			is.True(err != nil) // unknown keys should fail
+			is.Equal(err.Error(), tc.errtext)
+		})
+	}
+}
+
+func TestParseStrictNeedsPrefix(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	err := envflag.Parse(fs, nil, nil, envflag.WithStrict())
+	is.True(err != nil) // no prefix to be strict about
+}
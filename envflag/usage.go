@@ -0,0 +1,152 @@
+package envflag
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Usage creates a function for [flag.FlagSet.Usage]
+// which, in addition to the flag's type and usage text as with the default,
+// lists each flag's environment variable, default value,
+// and where the value would currently come from.
+// environ and opts should be the same as passed to [Parse],
+// so the usage reflects the actual prefixes and files in effect.
+//
+//	fs.Usage = envflag.Usage(fs, os.Environ(), opts...)
+//	err := envflag.Parse(fs, os.Args[1:], os.Environ(), opts...)
+func Usage(fs *flag.FlagSet, environ []string, opts ...Option) func() {
+	return func() {
+		o := newOptions(fs, opts)
+		w := fs.Output()
+
+		// usage is best effort, so an unreadable file is treated as if it were empty.
+		srcs := []source{}
+		if env, err := parseEnviron(environ); err == nil {
+			srcs = append(srcs, source{kind: FromEnv, entries: env})
+		}
+		for i := len(o.envFiles) - 1; i >= 0; i-- {
+			if entries, err := readEnvFile(o.envFiles[i]); err == nil {
+				srcs = append(srcs, source{kind: FromEnvFile, entries: entries})
+			}
+		}
+		hadArg := map[string]bool{}
+		fs.Visit(func(f *flag.Flag) { hadArg[f.Name] = true })
+
+		// the config file flag is resolved as by Parse, from its arg or the sources so far.
+		configFile := o.configFile
+		if f := fs.Lookup(o.configFlag); f != nil {
+			path := f.Value.String()
+			if origin, e, ok := o.find(srcs, f.Name); ok && !hadArg[f.Name] && origin.Kind != FromSecretFile {
+				path = e.value
+			}
+			if path != "" {
+				configFile = path
+			}
+		}
+		if configFile != "" {
+			if entries, err := readConfigFile(configFile); err == nil {
+				srcs = append(srcs, source{kind: FromConfigFile, entries: entries, byFlag: true})
+			}
+		}
+
+		if fs.Name() == "" {
+			fmt.Fprintf(w, "Usage:\n")
+		} else {
+			fmt.Fprintf(w, "Usage of %s:\n", fs.Name())
+		}
+		fs.VisitAll(func(f *flag.Flag) {
+			typeName, usage := flag.UnquoteUsage(f)
+			line := "  -" + f.Name
+			if typeName != "" {
+				line += " " + typeName
+			}
+
+			details := []string{"env " + strings.Join(o.envKeys(f.Name), " or ")}
+			if def := o.defaultValue(f); def != "" {
+				details = append(details, "default "+def)
+			}
+			// the args aren't known here, so neither is the position of one.
+			from := "arg"
+			if !hadArg[f.Name] {
+				origin, _, _ := o.find(srcs, f.Name)
+				from = origin.String()
+			}
+			details = append(details, "set from "+from)
+
+			fmt.Fprintf(w, "%s\n    \t%s (%s)\n", line, strings.ReplaceAll(usage, "\n", "\n    \t"), strings.Join(details, ", "))
+		})
+	}
+}
+
+// WriteMarkdown documents every flag in fs as a Markdown table,
+// listing the flag, environment variable, default, and usage.
+func WriteMarkdown(w io.Writer, fs *flag.FlagSet, opts ...Option) error {
+	o := newOptions(fs, opts)
+	cell := strings.NewReplacer("|", `\|`, "\n", " ")
+
+	lines := []string{
+		"| Flag | Environment | Default | Description |",
+		"| ---- | ----------- | ------- | ----------- |",
+	}
+	fs.VisitAll(func(f *flag.Flag) {
+		typeName, usage := flag.UnquoteUsage(f)
+		name := "-" + f.Name
+		if typeName != "" {
+			name += " " + typeName
+		}
+		def := ""
+		if d := o.defaultValue(f); d != "" {
+			def = "`" + d + "`"
+		}
+		lines = append(lines, fmt.Sprintf("| `%s` | `%s` | %s | %s |",
+			name, strings.Join(o.envKeys(f.Name), "`, `"), cell.Replace(def), cell.Replace(usage)))
+	})
+
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+// WriteEnvExample writes a `.env.example` style file for fs,
+// with every flag as a commented out assignment of its default value,
+// preceded by its usage as a comment.
+// The output is suitable for use with [WithEnvFile] once uncommented.
+func WriteEnvExample(w io.Writer, fs *flag.FlagSet, opts ...Option) error {
+	o := newOptions(fs, opts)
+
+	blocks := []string{}
+	fs.VisitAll(func(f *flag.Flag) {
+		_, usage := flag.UnquoteUsage(f)
+		def := ""
+		if !o.isSensitive(f) {
+			def = quoteEnvValue(o.defaultValue(f))
+		}
+		blocks = append(blocks, fmt.Sprintf("# %s\n# %s=%s\n",
+			strings.ReplaceAll(usage, "\n", "\n# "), o.envKeys(f.Name)[0], def))
+	})
+
+	_, err := io.WriteString(w, strings.Join(blocks, "\n"))
+	return err
+}
+
+// defaultValue is the default for f to document,
+// or empty if it is the zero value, or [Redacted] if sensitive.
+func (o *options) defaultValue(f *flag.Flag) string {
+	switch {
+	case f.DefValue == "0", f.DefValue == "false":
+		return ""
+	case f.DefValue != "" && o.isSensitive(f):
+		return Redacted
+	}
+	return f.DefValue
+}
+
+// quoteEnvValue double quotes a value if [WithEnvFile] would otherwise misread it.
+func quoteEnvValue(v string) string {
+	if !strings.ContainsAny(v, " \t\n\"'#\\") {
+		return v
+	}
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`)
+	return `"` + escape.Replace(v) + `"`
+}
@@ -0,0 +1,170 @@
+package envflag_test
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+
+	"importfromprojectlocally/envflag"
+)
+
+func TestUsage(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	out := &bytes.Buffer{}
+	fs := flag.NewFlagSet("myapp", flag.ContinueOnError)
+	fs.SetOutput(out)
+	fs.Int("port", 8000, "network `port` to listen on")
+	fs.String("api-key", "dev-key", "key for the | upstream api")
+	fs.Duration("timeout", 5*time.Second, "request timeout")
+	fs.String("greeting", "hello world", "how to say hi")
+	fs.Bool("verbose", false, "log more")
+	env := []string{"MYAPP_TIMEOUT=1s", "VERBOSE=true"}
+	opts := []envflag.Option{
+		envflag.WithPrefix("myapp"),
+		envflag.WithUnprefixedFallback(),
+		envflag.WithSensitive("api-key"),
+	}
+	fs.Usage = envflag.Usage(fs, env, opts...)
+
+	err := envflag.Parse(fs, []string{"-port", "9000", "-h"}, env, opts...)
+	is.True(err != nil) // -h always returns flag.ErrHelp
+
+	expected := `Usage of myapp:
+  -api-key string
+    	key for the | upstream api (env MYAPP_API_KEY or API_KEY, default [redacted], set from default)
+  -greeting string
+    	how to say hi (env MYAPP_GREETING or GREETING, default hello world, set from default)
+  -port port
+    	network port to listen on (env MYAPP_PORT or PORT, default 8000, set from arg)
+  -timeout duration
+    	request timeout (env MYAPP_TIMEOUT or TIMEOUT, default 5s, set from env MYAPP_TIMEOUT)
+  -verbose
+    	log more (env MYAPP_VERBOSE or VERBOSE, set from env VERBOSE)
+`
+	t.Log(out.String())
+	is.Equal(out.String(), expected)
+}
+
+func TestUsageConfigFileFlag(t *testing.T) {
+	t.Parallel()
+
+	// each case names the config file given its path.
+	testCases := map[string]struct {
+		args func(path string) []string
+		env  func(path string) []string
+	}{
+		"from arg": {
+			func(path string) []string { return []string{"-config", path, "-h"} },
+			func(string) []string { return nil },
+		},
+		"from env": {
+			func(string) []string { return []string{"-h"} },
+			func(path string) []string { return []string{"MYAPP_CONFIG=" + path} },
+		},
+	}
+
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			path := writeFile(t, "app.conf", "port=9001\n")
+			out := &bytes.Buffer{}
+			fs := flag.NewFlagSet("myapp", flag.ContinueOnError)
+			fs.SetOutput(out)
+			fs.String("config", "", "config file")
+			fs.Int("port", 8000, "network port to listen on")
+			env := tc.env(path)
+			opts := []envflag.Option{envflag.WithPrefix("myapp"), envflag.WithConfigFileFlag("config")}
+			fs.Usage = envflag.Usage(fs, env, opts...)
+
+			err := envflag.Parse(fs, tc.args(path), env, opts...)
+			is.True(err != nil) // -h always returns flag.ErrHelp
+			t.Log(out.String())
+			is.True(strings.Contains(out.String(), "default 8000, set from config-file "+path)) // port from the named file
+		})
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	out := &bytes.Buffer{}
+	fs := flag.NewFlagSet("myapp", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Int("port", 8000, "network `port` to listen on")
+	fs.String("api-key", "dev-key", "key for the | upstream api")
+	fs.Duration("timeout", 5*time.Second, "request timeout")
+	fs.String("greeting", "hello world", "how to say hi")
+	fs.Bool("verbose", false, "log more")
+	is.NoErr(envflag.WriteMarkdown(out, fs, envflag.WithPrefix("myapp"), envflag.WithSensitive("api-key")))
+
+	expected := strings.Join([]string{
+		"| Flag | Environment | Default | Description |",
+		"| ---- | ----------- | ------- | ----------- |",
+		"| `-api-key string` | `MYAPP_API_KEY` | `[redacted]` | key for the \\| upstream api |",
+		"| `-greeting string` | `MYAPP_GREETING` | `hello world` | how to say hi |",
+		"| `-port port` | `MYAPP_PORT` | `8000` | network port to listen on |",
+		"| `-timeout duration` | `MYAPP_TIMEOUT` | `5s` | request timeout |",
+		"| `-verbose` | `MYAPP_VERBOSE` |  | log more |",
+	}, "\n") + "\n"
+	t.Log(out.String())
+	is.Equal(out.String(), expected)
+}
+
+func TestWriteEnvExample(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	out := &bytes.Buffer{}
+	fs := flag.NewFlagSet("myapp", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Int("port", 8000, "network `port` to listen on")
+	fs.String("api-key", "dev-key", "key for the | upstream api")
+	fs.Duration("timeout", 5*time.Second, "request timeout")
+	fs.String("greeting", "hello world", "how to say hi")
+	fs.Bool("verbose", false, "log more")
+	opts := []envflag.Option{envflag.WithPrefix("myapp"), envflag.WithSensitive("api-key")}
+	is.NoErr(envflag.WriteEnvExample(out, fs, opts...))
+
+	expected := `# key for the | upstream api
+# MYAPP_API_KEY=
+
+# how to say hi
+# MYAPP_GREETING="hello world"
+
+# network port to listen on
+# MYAPP_PORT=8000
+
+# request timeout
+# MYAPP_TIMEOUT=5s
+
+# log more
+# MYAPP_VERBOSE=
+`
+	t.Log(out.String())
+	is.Equal(out.String(), expected)
+
+	// the example should round trip through WithEnvFile once uncommented.
+	envFile := writeFile(t, ".env.example", strings.ReplaceAll(out.String(), "# MYAPP", "MYAPP"))
+	// round trip into a fresh flag set, with the same flags.
+	fs2 := flag.NewFlagSet("myapp", flag.ContinueOnError)
+	fs2.SetOutput(io.Discard)
+	fs2.Int("port", 8000, "network `port` to listen on")
+	fs2.String("api-key", "dev-key", "key for the | upstream api")
+	fs2.Duration("timeout", 5*time.Second, "request timeout")
+	fs2.String("greeting", "hello world", "how to say hi")
+	fs2.Bool("verbose", false, "log more")
+	is.NoErr(envflag.Parse(fs2, nil, nil, append(opts, envflag.WithEnvFile(envFile))...))
+	is.Equal(fs2.Lookup("greeting").Value.String(), "hello world")
+	is.Equal(fs2.Lookup("timeout").Value.String(), "5s")
+}
@@ -0,0 +1,44 @@
+//go:build unix
+
+package envflag_test
+
+import (
+	"bytes"
+	"flag"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+
+	"importfromprojectlocally/envflag"
+)
+
+func TestUsageSecretFileNotRead(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	// opening a fifo blocks until it is written to, so any read would hang.
+	fifo := filepath.Join(t.TempDir(), "secret")
+	is.NoErr(syscall.Mkfifo(fifo, 0o600))
+
+	out := &bytes.Buffer{}
+	fs := flag.NewFlagSet("myapp", flag.ContinueOnError)
+	fs.SetOutput(out)
+	fs.String("api-key", "", "test secret")
+	usage := envflag.Usage(fs, []string{"API_KEY_FILE=" + fifo}, envflag.WithSecretFiles(0))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		usage()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("usage read the secret file")
+	}
+	is.True(strings.Contains(out.String(), "set from secret-file "+fifo+" API_KEY_FILE"))
+}
@@ -0,0 +1,135 @@
+package envflag
+
+import (
+	"cmp"
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// validator checks flag values once every source has been applied,
+// given the origin of each flag's value.
+type validator func(fs *flag.FlagSet, origins Provenance) error
+
+// WithRequired makes Parse return an error if any of the flag names
+// is not set by some source, ie if it would be left at its default.
+func WithRequired(names ...string) Option {
+	return func(o *options) {
+		for _, name := range names {
+			name := name
+			o.validators = append(o.validators, func(fs *flag.FlagSet, origins Provenance) error {
+				if _, err := lookupFlag(fs, name); err != nil {
+					return err
+				}
+				if origins[name].Kind == FromDefault {
+					return fmt.Errorf("required flag -%v or env var %v is not set",
+						name, strings.Join(o.envKeys(name), " or "))
+				}
+				return nil
+			})
+		}
+	}
+}
+
+// WithValidation adds a check over the whole flag set,
+// for constraints the other validations cannot express.
+// fn is called after all sources have been applied,
+// and any error it returns is included in the error from Parse.
+// On a [Reloader] reload, fs is a staged copy holding the new values.
+func WithValidation(fn func(fs *flag.FlagSet) error) Option {
+	return func(o *options) {
+		o.validators = append(o.validators, func(fs *flag.FlagSet, _ Provenance) error {
+			return fn(fs)
+		})
+	}
+}
+
+// Range requires the value of flag name to be between lo and hi inclusive.
+// The flag's Value must implement [flag.Getter] returning a T,
+// as all the flag types from the standard library do,
+// ie Range("port", 1, 65535) for a flag from fs.Int.
+func Range[T cmp.Ordered](name string, lo, hi T) Option {
+	return func(o *options) {
+		o.validators = append(o.validators, func(fs *flag.FlagSet, _ Provenance) error {
+			f, err := lookupFlag(fs, name)
+			if err != nil {
+				return err
+			}
+			getter, ok := f.Value.(flag.Getter)
+			if !ok {
+				return fmt.Errorf("flag -%v does not implement flag.Getter", name)
+			}
+			v, ok := getter.Get().(T)
+			if !ok {
+				return fmt.Errorf("flag -%v is a %T, not %T", name, getter.Get(), v)
+			}
+			if v < lo || v > hi {
+				return fmt.Errorf("flag -%v: %v is not between %v and %v", name, v, lo, hi)
+			}
+			return nil
+		})
+	}
+}
+
+// OneOf requires the value of flag name to be one of allowed,
+// as compared to the Value's String().
+func OneOf(name string, allowed ...string) Option {
+	return func(o *options) {
+		o.validators = append(o.validators, func(fs *flag.FlagSet, _ Provenance) error {
+			f, err := lookupFlag(fs, name)
+			if err != nil {
+				return err
+			}
+			v := f.Value.String()
+			for _, a := range allowed {
+				if v == a {
+					return nil
+				}
+			}
+			return fmt.Errorf("flag -%v: %q is not one of %v", name, v, strings.Join(allowed, ", "))
+		})
+	}
+}
+
+// MutuallyExclusive allows at most one of the flag names to be set by any source.
+func MutuallyExclusive(names ...string) Option {
+	return func(o *options) {
+		o.validators = append(o.validators, func(fs *flag.FlagSet, origins Provenance) error {
+			set := []string{}
+			for _, name := range names {
+				if _, err := lookupFlag(fs, name); err != nil {
+					return err
+				}
+				if origins[name].Kind != FromDefault {
+					set = append(set, "-"+name)
+				}
+			}
+			if len(set) > 1 {
+				return fmt.Errorf("flags %v are mutually exclusive", strings.Join(set, ", "))
+			}
+			return nil
+		})
+	}
+}
+
+// validate runs every validator, joining all the errors,
+// so every problem can be fixed at once.
+func (o *options) validate(fs *flag.FlagSet) error {
+	errs := []error{}
+	for _, v := range o.validators {
+		if err := v(fs, o.origins); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// lookupFlag finds flag name, or fails if it is not defined,
+// as a validation naming an undefined flag is a programming error.
+func lookupFlag(fs *flag.FlagSet, name string) (*flag.Flag, error) {
+	if f := fs.Lookup(name); f != nil {
+		return f, nil
+	}
+	return nil, fmt.Errorf("flag %v is not defined", name)
+}
@@ -0,0 +1,141 @@
+package envflag_test
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+
+	"importfromprojectlocally/envflag"
+)
+
+func TestParseValidation(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		args    []string
+		env     []string
+		opts    []envflag.Option
+		errtext string
+	}{
+		"required from env": {
+			nil,
+			[]string{"DB_URL=postgres://"},
+			[]envflag.Option{envflag.WithRequired("db-url")},
+			"",
+		},
+		"required from arg": {
+			[]string{"-db-url", "postgres://"},
+			nil,
+			[]envflag.Option{envflag.WithRequired("db-url")},
+			"",
+		},
+		"required missing": {
+			nil, nil,
+			[]envflag.Option{envflag.WithRequired("db-url"), envflag.WithPrefix("myapp")},
+			"required flag -db-url or env var MYAPP_DB_URL is not set",
+		},
+		"required undefined": {
+			nil, nil,
+			[]envflag.Option{envflag.WithRequired("nope")},
+			"flag nope is not defined",
+		},
+		"range ok": {
+			[]string{"-port", "65535"},
+			nil,
+			[]envflag.Option{envflag.Range("port", 1, 65535)},
+			"",
+		},
+		"range out": {
+			nil,
+			[]string{"PORT=70000"},
+			[]envflag.Option{envflag.Range("port", 1, 65535)},
+			"flag -port: 70000 is not between 1 and 65535",
+		},
+		"range duration": {
+			[]string{"-timeout", "1h"},
+			nil,
+			[]envflag.Option{envflag.Range("timeout", time.Second, time.Minute)},
+			"flag -timeout: 1h0m0s is not between 1s and 1m0s",
+		},
+		"range wrong type": {
+			nil, nil,
+			[]envflag.Option{envflag.Range("port", int64(1), 2)},
+			"flag -port is a int, not int64",
+		},
+		"one of ok": {
+			nil,
+			[]string{"FORMAT=json"},
+			[]envflag.Option{envflag.OneOf("format", "json", "text")},
+			"",
+		},
+		"one of bad": {
+			nil,
+			[]string{"FORMAT=yaml"},
+			[]envflag.Option{envflag.OneOf("format", "json", "text")},
+			`flag -format: "yaml" is not one of json, text`,
+		},
+		"exclusive ok": {
+			[]string{"-db-url", "x"},
+			nil,
+			[]envflag.Option{envflag.MutuallyExclusive("db-url", "db-file")},
+			"",
+		},
+		"exclusive across sources": {
+			[]string{"-db-url", "x"},
+			[]string{"DB_FILE=y"},
+			[]envflag.Option{envflag.MutuallyExclusive("db-url", "db-file")},
+			"flags -db-url, -db-file are mutually exclusive",
+		},
+		"custom validation": {
+			nil, nil,
+			[]envflag.Option{envflag.WithValidation(func(fs *flag.FlagSet) error {
+				if fs.Lookup("format").Value.String() == "text" {
+					return errors.New("text is not allowed in prod")
+				}
+				return nil
+			})},
+			"text is not allowed in prod",
+		},
+		"every problem is reported": {
+			nil,
+			[]string{"PORT=0", "FORMAT=yaml", "TIMEOUT=soon"},
+			[]envflag.Option{
+				envflag.WithRequired("db-url"),
+				envflag.Range("port", 1, 65535),
+				envflag.OneOf("format", "json", "text"),
+			},
+			"setting flag timeout from env var TIMEOUT: parse error\n" +
+				"required flag -db-url or env var DB_URL is not set\n" +
+				"flag -port: 0 is not between 1 and 65535\n" +
+				`flag -format: "yaml" is not one of json, text`,
+		},
+	}
+
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			fs.String("db-url", "", "test required")
+			fs.String("db-file", "", "test exclusive")
+			fs.Int("port", 8000, "test range")
+			fs.Duration("timeout", time.Second, "test duration range")
+			fs.String("format", "text", "test one of")
+
+			err := envflag.Parse(fs, tc.args, tc.env, tc.opts...)
+			if tc.errtext == "" {
+				is.NoErr(err)
+				return
+			}
+			is.True(err != nil) // should fail validation
+			is.Equal(err.Error(), tc.errtext)
+		})
+	}
+}
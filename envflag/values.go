@@ -0,0 +1,354 @@
+package envflag
+
+import (
+	"encoding"
+	"fmt"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Slice is a [ListFlag] holding a []T, appending the items of each Set,
+// split on commas as for env values, with the same quoting,
+// so `-port 80 -port 443`, `-port 80,443`, and `PORTS=80,443` are equivalent.
+// The first Set replaces the default, rather than appending to it.
+type Slice[T any] struct {
+	values *[]T
+	parse  func(string) (T, error)
+	set    bool
+}
+
+// NewSlice creates a Slice storing into p, with the current contents of p as the default.
+// Items are parsed by parse, or if nil, as per the flag of the same type
+// for string, bool, int, int64, uint, uint64, float64, and time.Duration,
+// or via UnmarshalText if *T implements encoding.TextUnmarshaler.
+//
+//	fs.Var(envflag.NewSlice(&cfg.Ports, nil), "port", "ports to listen on, may be repeated")
+func NewSlice[T any](p *[]T, parse func(string) (T, error)) *Slice[T] {
+	if parse == nil {
+		parse = parseText[T]
+	}
+	return &Slice[T]{values: p, parse: parse}
+}
+
+// Set satisfies flag.Value, appending the items of v, or none if any fail to parse.
+func (s *Slice[T]) Set(v string) error {
+	texts, err := splitList(v, ',')
+	if err != nil {
+		return err
+	}
+	items := make([]T, 0, len(texts))
+	for _, text := range texts {
+		item, err := s.parse(text)
+		if err != nil {
+			return err
+		}
+		items = append(items, item)
+	}
+	if !s.set {
+		*s.values = nil
+		s.set = true
+	}
+	*s.values = append(*s.values, items...)
+	return nil
+}
+
+// String satisfies flag.Value, listing the items as they would be given in env.
+func (s *Slice[T]) String() string {
+	if s == nil || s.values == nil {
+		return ""
+	}
+	items := make([]string, 0, len(*s.values))
+	for _, v := range *s.values {
+		items = append(items, quoteListItem(formatText(v)))
+	}
+	return strings.Join(items, ",")
+}
+
+// Get satisfies flag.Getter.
+func (s *Slice[T]) Get() any {
+	return *s.values
+}
+
+// IsListFlag satisfies ListFlag.
+func (s *Slice[T]) IsListFlag() bool {
+	return true
+}
+
+// splitsItems satisfies itemSplitter.
+func (s *Slice[T]) splitsItems() bool {
+	return true
+}
+
+// Map is a [ListFlag] holding a map[string]V, adding the `key=value` items of each Set,
+// split as per [Slice], so `-label a=1 -label b=2`, `-label a=1,b=2`, and `LABELS=a=1,b=2` are equivalent.
+// The first Set replaces the default, rather than adding to it.
+type Map[V any] struct {
+	values *map[string]V
+	parse  func(string) (V, error)
+	set    bool
+}
+
+// NewMap creates a Map storing into p, with the current contents of p as the default.
+// Values are parsed by parse, or if nil, as per [NewSlice].
+func NewMap[V any](p *map[string]V, parse func(string) (V, error)) *Map[V] {
+	if parse == nil {
+		parse = parseText[V]
+	}
+	return &Map[V]{values: p, parse: parse}
+}
+
+// Set satisfies flag.Value, adding the `key=value` items of kvs, or none if any fail to parse.
+func (m *Map[V]) Set(kvs string) error {
+	texts, err := splitList(kvs, ',')
+	if err != nil {
+		return err
+	}
+	items := make(map[string]V, len(texts))
+	for _, kv := range texts {
+		k, v, found := strings.Cut(kv, "=")
+		if !found || k == "" {
+			return fmt.Errorf("%q is not key=value", kv)
+		}
+		value, err := m.parse(v)
+		if err != nil {
+			return fmt.Errorf("key %v: %w", k, err)
+		}
+		items[k] = value
+	}
+	if !m.set || *m.values == nil {
+		*m.values = map[string]V{}
+		m.set = true
+	}
+	for k, v := range items {
+		(*m.values)[k] = v
+	}
+	return nil
+}
+
+// String satisfies flag.Value, listing the items by key as they would be given in env.
+func (m *Map[V]) String() string {
+	if m == nil || m.values == nil {
+		return ""
+	}
+	keys := make([]string, 0, len(*m.values))
+	for k := range *m.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	items := make([]string, 0, len(keys))
+	for _, k := range keys {
+		items = append(items, quoteListItem(k+"="+formatText((*m.values)[k])))
+	}
+	return strings.Join(items, ",")
+}
+
+// Get satisfies flag.Getter.
+func (m *Map[V]) Get() any {
+	return *m.values
+}
+
+// IsListFlag satisfies ListFlag.
+func (m *Map[V]) IsListFlag() bool {
+	return true
+}
+
+// splitsItems satisfies itemSplitter.
+func (m *Map[V]) splitsItems() bool {
+	return true
+}
+
+// Enum is a flag.Value for a string restricted to a set of allowed values.
+type Enum struct {
+	value   *string
+	allowed []string
+}
+
+// NewEnum creates an Enum storing into p, with the current value of p as the default.
+//
+//	format := "text"
+//	fs.Var(envflag.NewEnum(&format, "text", "json"), "format", "output format")
+func NewEnum(p *string, allowed ...string) *Enum {
+	return &Enum{value: p, allowed: allowed}
+}
+
+// Set satisfies flag.Value.
+func (e *Enum) Set(v string) error {
+	for _, a := range e.allowed {
+		if v == a {
+			*e.value = v
+			return nil
+		}
+	}
+	return fmt.Errorf("%q is not one of %v", v, strings.Join(e.allowed, ", "))
+}
+
+// String satisfies flag.Value.
+func (e *Enum) String() string {
+	if e == nil || e.value == nil {
+		return ""
+	}
+	return *e.value
+}
+
+// Get satisfies flag.Getter.
+func (e *Enum) Get() any {
+	return *e.value
+}
+
+// ByteSize is a flag.Value for a number of bytes,
+// given with an optional decimal (kB, MB, GB, TB) or binary (KiB, MiB, GiB, TiB) unit,
+// ie `512`, `1.5GB`, or `10MiB`. Units are case insensitive.
+// As it implements flag.Value itself, a ByteSize config field works with [Bind].
+type ByteSize uint64
+
+// byteUnits are the suffixes ByteSize accepts, largest first within each base,
+// as String prefers the largest exact unit.
+//
+//nolint:gochecknoglobals // Go has no const slices.
+var byteUnits = []struct {
+	suffix string
+	size   uint64
+}{
+	{"TiB", 1 << 40}, {"GiB", 1 << 30}, {"MiB", 1 << 20}, {"KiB", 1 << 10},
+	{"TB", 1e12}, {"GB", 1e9}, {"MB", 1e6}, {"kB", 1e3},
+	{"B", 1},
+}
+
+// Set satisfies flag.Value.
+func (b *ByteSize) Set(s string) error {
+	number, size := strings.TrimSpace(s), uint64(1)
+	for _, u := range byteUnits {
+		if len(number) > len(u.suffix) && strings.EqualFold(number[len(number)-len(u.suffix):], u.suffix) {
+			number, size = strings.TrimSpace(number[:len(number)-len(u.suffix)]), u.size
+			break
+		}
+	}
+	if n, err := strconv.ParseUint(number, 10, 64); err == nil {
+		if n > math.MaxUint64/size {
+			return fmt.Errorf("byte size %q is out of range", s)
+		}
+		*b = ByteSize(n * size)
+		return nil
+	}
+	f, err := strconv.ParseFloat(number, 64)
+	if err != nil || f < 0 {
+		return fmt.Errorf("invalid byte size %q", s)
+	}
+	if f*float64(size) >= math.MaxUint64 {
+		return fmt.Errorf("byte size %q is out of range", s)
+	}
+	*b = ByteSize(f * float64(size))
+	return nil
+}
+
+// String satisfies flag.Value, using the largest unit which is exact.
+func (b *ByteSize) String() string {
+	if b == nil || *b == 0 {
+		return "0"
+	}
+	for _, u := range byteUnits {
+		if uint64(*b)%u.size == 0 {
+			return strconv.FormatUint(uint64(*b)/u.size, 10) + u.suffix
+		}
+	}
+	return strconv.FormatUint(uint64(*b), 10) + "B"
+}
+
+// Get satisfies flag.Getter.
+func (b *ByteSize) Get() any {
+	return *b
+}
+
+// URL is a flag.Value for a url.URL, optionally restricted to a set of schemes.
+type URL struct {
+	url     *url.URL
+	schemes []string
+}
+
+// NewURL creates a URL storing into p, with the current value of p as the default.
+// If any schemes are given, the URL must be absolute with one of them.
+//
+//	fs.Var(envflag.NewURL(&cfg.API, "https"), "api", "API base URL")
+func NewURL(p *url.URL, schemes ...string) *URL {
+	return &URL{url: p, schemes: schemes}
+}
+
+// Set satisfies flag.Value.
+func (u *URL) Set(s string) error {
+	parsed, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	if len(u.schemes) > 0 {
+		ok := false
+		for _, scheme := range u.schemes {
+			ok = ok || strings.EqualFold(parsed.Scheme, scheme)
+		}
+		if !ok {
+			return fmt.Errorf("scheme of %q is not one of %v", s, strings.Join(u.schemes, ", "))
+		}
+	}
+	*u.url = *parsed
+	return nil
+}
+
+// String satisfies flag.Value.
+func (u *URL) String() string {
+	if u == nil || u.url == nil {
+		return ""
+	}
+	return u.url.String()
+}
+
+// Get satisfies flag.Getter.
+func (u *URL) Get() any {
+	return u.url
+}
+
+// parseText parses s as a T, as the flag package would for its types,
+// or via UnmarshalText.
+func parseText[T any](s string) (T, error) {
+	var v T
+	var err error
+	switch p := any(&v).(type) {
+	case *string:
+		*p = s
+	case *bool:
+		*p, err = strconv.ParseBool(s)
+	case *int:
+		var n int64
+		n, err = strconv.ParseInt(s, 0, strconv.IntSize)
+		*p = int(n)
+	case *int64:
+		*p, err = strconv.ParseInt(s, 0, 64)
+	case *uint:
+		var n uint64
+		n, err = strconv.ParseUint(s, 0, strconv.IntSize)
+		*p = uint(n)
+	case *uint64:
+		*p, err = strconv.ParseUint(s, 0, 64)
+	case *float64:
+		*p, err = strconv.ParseFloat(s, 64)
+	case *time.Duration:
+		*p, err = time.ParseDuration(s)
+	case encoding.TextUnmarshaler:
+		err = p.UnmarshalText([]byte(s))
+	default:
+		err = fmt.Errorf("no parser for %T", v)
+	}
+	return v, err
+}
+
+// formatText is the inverse of parseText.
+func formatText[T any](v T) string {
+	if m, ok := any(&v).(encoding.TextMarshaler); ok {
+		if text, err := m.MarshalText(); err == nil {
+			return string(text)
+		}
+	}
+	return fmt.Sprint(v)
+}
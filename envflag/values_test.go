@@ -0,0 +1,265 @@
+package envflag_test
+
+import (
+	"flag"
+	"io"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/matryer/is"
+
+	"importfromprojectlocally/envflag"
+)
+
+func TestValues(t *testing.T) {
+	t.Parallel()
+
+	type target struct {
+		ports   []int
+		names   []string
+		waits   []time.Duration
+		labels  map[string]string
+		weights map[string]int
+		format  string
+		size    envflag.ByteSize
+		api     url.URL
+	}
+
+	testCases := map[string]struct {
+		args   []string
+		env    []string
+		check  func(is *is.I, tgt target)
+		errtxt string
+	}{
+		"defaults": {
+			nil, nil,
+			func(is *is.I, tgt target) {
+				is.Equal(tgt.ports, []int{8000})
+				is.Equal(tgt.format, "text")
+				is.Equal(tgt.size, envflag.ByteSize(0))
+			},
+			"",
+		},
+		"slice from args": {
+			[]string{"-ports", "80,443", "-names", `"a,b",c\,d`, "-waits", "1s", "-waits", "1m"},
+			nil,
+			func(is *is.I, tgt target) {
+				is.Equal(tgt.ports, []int{80, 443}) // default replaced
+				is.Equal(tgt.names, []string{"a,b", "c,d"})
+				is.Equal(tgt.waits, []time.Duration{time.Second, time.Minute}) // repeated args append
+			},
+			"",
+		},
+		"slice from env": {
+			nil,
+			[]string{"PORTS=80,443", `NAMES="a,b",c\,d`, "WAITS=1s,1m"},
+			func(is *is.I, tgt target) {
+				is.Equal(tgt.ports, []int{80, 443}) // same as args
+				is.Equal(tgt.names, []string{"a,b", "c,d"})
+				is.Equal(tgt.waits, []time.Duration{time.Second, time.Minute})
+			},
+			"",
+		},
+		"map from args": {
+			[]string{"-labels", "team=core,env=prod", "-labels", "url=a=b", "-weights", "a=1"},
+			nil,
+			func(is *is.I, tgt target) {
+				is.Equal(tgt.labels, map[string]string{"team": "core", "env": "prod", "url": "a=b"})
+				is.Equal(tgt.weights, map[string]int{"a": 1})
+			},
+			"",
+		},
+		"map from env": {
+			nil,
+			[]string{"LABELS=team=core,env=prod,url=a=b", "WEIGHTS=a=1"},
+			func(is *is.I, tgt target) {
+				is.Equal(tgt.labels, map[string]string{"team": "core", "env": "prod", "url": "a=b"}) // same as args
+				is.Equal(tgt.weights, map[string]int{"a": 1})
+			},
+			"",
+		},
+		"enum, byte size, and url": {
+			[]string{"-format", "json"},
+			[]string{"SIZE=10MiB", "API=https://api.example.com/v1"},
+			func(is *is.I, tgt target) {
+				is.Equal(tgt.format, "json")
+				is.Equal(tgt.size, envflag.ByteSize(10<<20))
+				is.Equal(tgt.api.Host, "api.example.com")
+			},
+			"",
+		},
+		"bad slice item": {
+			nil,
+			[]string{"PORTS=80,http"},
+			nil,
+			`setting flag ports from env var PORTS: strconv.Atoi: parsing "http": invalid syntax`,
+		},
+		"bad slice arg": {
+			[]string{"-ports", "80,http"},
+			nil,
+			nil,
+			`parsing arg flags: invalid value "80,http" for flag -ports: strconv.Atoi: parsing "http": invalid syntax`,
+		},
+		"bad map item": {
+			nil,
+			[]string{"WEIGHTS=a=1,b"},
+			nil,
+			`setting flag weights from env var WEIGHTS: "b" is not key=value`,
+		},
+		"bad enum": {
+			[]string{"-format", "yaml"},
+			nil,
+			nil,
+			`parsing arg flags: invalid value "yaml" for flag -format: "yaml" is not one of text, json`,
+		},
+		"bad scheme": {
+			nil,
+			[]string{"API=ftp://example.com"},
+			nil,
+			`setting flag api from env var API: scheme of "ftp://example.com" is not one of http, https`,
+		},
+	}
+
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			tgt := target{ports: []int{8000}, format: "text"}
+			fs := flag.NewFlagSet("test-"+name, flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			fs.Var(envflag.NewSlice(&tgt.ports, strconv.Atoi), "ports", "test slice with parser")
+			fs.Var(envflag.NewSlice(&tgt.names, nil), "names", "test slice")
+			fs.Var(envflag.NewSlice(&tgt.waits, nil), "waits", "test slice of durations")
+			fs.Var(envflag.NewMap(&tgt.labels, nil), "labels", "test map")
+			fs.Var(envflag.NewMap(&tgt.weights, nil), "weights", "test map of ints")
+			fs.Var(envflag.NewEnum(&tgt.format, "text", "json"), "format", "test enum")
+			fs.Var(&tgt.size, "size", "test byte size")
+			fs.Var(envflag.NewURL(&tgt.api, "http", "https"), "api", "test url")
+			err := envflag.Parse(fs, tc.args, tc.env)
+			if tc.errtxt != "" {
+				is.True(err != nil) // should fail
+				is.Equal(err.Error(), tc.errtxt)
+				return
+			}
+			is.NoErr(err)
+			tc.check(is, tgt)
+		})
+	}
+}
+
+func TestValuesJSONConfig(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	configFile := writeFile(t, "app.json", `{"names": ["a,b", "c\"d"], "ports": [80, 443]}`)
+	names, ports := []string{}, []int{}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(envflag.NewSlice(&names, nil), "names", "test slice")
+	fs.Var(envflag.NewSlice(&ports, strconv.Atoi), "ports", "test slice with parser")
+	is.NoErr(envflag.Parse(fs, nil, nil, envflag.WithConfigFile(configFile)))
+
+	is.Equal(names, []string{"a,b", `c"d`}) // array items are not split further
+	is.Equal(ports, []int{80, 443})
+}
+
+func TestValuesString(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	ports, names, labels, size := []int{8000}, []string{}, map[string]string{}, envflag.ByteSize(0)
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(envflag.NewSlice(&ports, strconv.Atoi), "ports", "test slice with parser")
+	fs.Var(envflag.NewSlice(&names, nil), "names", "test slice")
+	fs.Var(envflag.NewMap(&labels, nil), "labels", "test map")
+	fs.Var(&size, "size", "test byte size")
+	is.NoErr(envflag.Parse(fs, nil, []string{
+		`NAMES="a,b",c\"d,e`,
+		"LABELS=b=2,a=1",
+		"SIZE=1.5GB",
+	}))
+
+	// Strings can be fed back into env to give the same value.
+	is.Equal(fs.Lookup("names").Value.String(), `"a,b","c\"d",e`)
+	is.Equal(fs.Lookup("labels").Value.String(), "a=1,b=2")
+	is.Equal(fs.Lookup("size").Value.String(), "1500MB")
+	is.Equal(fs.Lookup("ports").DefValue, "8000")
+
+	names2, labels2, size2 := []string{}, map[string]string{}, envflag.ByteSize(0)
+	fs2 := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs2.Var(envflag.NewSlice(&names2, nil), "names", "test slice")
+	fs2.Var(envflag.NewMap(&labels2, nil), "labels", "test map")
+	fs2.Var(&size2, "size", "test byte size")
+	is.NoErr(envflag.Parse(fs2, nil, []string{
+		"NAMES=" + fs.Lookup("names").Value.String(),
+		"LABELS=" + fs.Lookup("labels").Value.String(),
+		"SIZE=" + fs.Lookup("size").Value.String(),
+	}))
+	is.Equal(names2, names)
+	is.Equal(labels2, labels)
+	is.Equal(size2, size)
+}
+
+func TestByteSize(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		in     string
+		expect envflag.ByteSize
+		str    string
+		err    bool
+	}{
+		"bytes":          {"512", 512, "512B", false},
+		"binary":         {"10MiB", 10 << 20, "10MiB", false},
+		"decimal":        {"2kB", 2000, "2kB", false},
+		"fraction":       {"1.5GiB", 3 << 29, "1536MiB", false},
+		"case and space": {"4 gib", 4 << 30, "4GiB", false},
+		"zero":           {"0", 0, "0", false},
+		"no number":      {"MiB", 0, "", true},
+		"negative":       {"-1kB", 0, "", true},
+		"overflow":       {"20000000TiB", 0, "", true},
+	}
+
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			var b envflag.ByteSize
+			err := b.Set(tc.in)
+			if tc.err {
+				is.True(err != nil) // should fail to parse
+				return
+			}
+			is.NoErr(err)
+			is.Equal(b, tc.expect)
+			is.Equal(b.String(), tc.str)
+		})
+	}
+}
+
+func TestBindValues(t *testing.T) {
+	t.Parallel()
+	is := is.New(t)
+
+	cfg := struct {
+		Ports   []int
+		Tags    []string
+		Labels  map[string]string
+		MaxBody envflag.ByteSize `default:"1MiB"`
+	}{}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	is.NoErr(envflag.ParseStruct(fs, &cfg, []string{"-tags", "a", "-tags", "b"}, []string{"PORTS=80,443", "LABELS=a=1"}))
+
+	is.Equal(cfg.Ports, []int{80, 443})
+	is.Equal(cfg.Tags, []string{"a", "b"})
+	is.Equal(cfg.Labels, map[string]string{"a": "1"})
+	is.Equal(cfg.MaxBody, envflag.ByteSize(1<<20))
+	is.Equal(fs.Lookup("max-body").DefValue, "1MiB")
+}
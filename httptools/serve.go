@@ -11,6 +11,7 @@ import (
 	"sync"
 	"time"
 
+	"importfromprojectlocally/consterr"
 	"importfromprojectlocally/slogext"
 )
 
@@ -27,7 +28,8 @@ func (sw slogErrWriter) Write(p []byte) (int, error) {
 // Serve an http.Handler (which may be a mux like http.ServeMux, chi.Router, gorilla.Mux, etc)
 // on a given port with reasonable defaults.
 // Run until the supplied context is canceled, then try to shutdown gracefully.
-// Return the http.Server or shutdown error if unable to start or exit gracefully.
+// Return the http.Server and shutdown errors, joined per consterr.Join,
+// if unable to start or exit gracefully.
 // Will return nil, not http.ErrServerClosed, if the shutdown is graceful.
 func Serve(ctx context.Context, port int, mux http.Handler) error {
 	log := slogext.From(ctx).With("component", "http", "port", port)
@@ -89,13 +91,11 @@ func Serve(ctx context.Context, port int, mux http.Handler) error {
 
 	wg.Wait()
 
-	if srverr != nil {
-		return srverr
-	}
 	if shuterr != nil {
 		log.Error("during shutdown", slogext.Error(shuterr))
 	}
-
-	log.Info("HTTP service stopped")
-	return shuterr
+	if srverr == nil {
+		log.Info("HTTP service stopped")
+	}
+	return consterr.Join(srverr, shuterr)
 }
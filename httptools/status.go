@@ -0,0 +1,50 @@
+package httptools
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"importfromprojectlocally/consterr"
+)
+
+// StatusCode maps err to an HTTP status code per its [consterr.Class].
+// A nil err is 200 OK, and an Unclassified err, or one of a Class which isn't [consterr.Class.Known],
+// is 500 Internal Server Error,
+// other than a context deadline, which is 503 Service Unavailable.
+func StatusCode(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	switch consterr.ClassOf(err) {
+	case consterr.InvalidInput:
+		return http.StatusBadRequest
+	case consterr.NotFound:
+		return http.StatusNotFound
+	case consterr.Conflict:
+		return http.StatusConflict
+	case consterr.Unauthenticated:
+		return http.StatusUnauthorized
+	case consterr.Forbidden:
+		return http.StatusForbidden
+	case consterr.Retryable:
+		return http.StatusServiceUnavailable
+	case consterr.Internal, consterr.Unclassified:
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// WriteError responds to a request with the status code for err per [StatusCode].
+// The body is the error message if its Class is user facing,
+// otherwise just the status text, so internal details aren't leaked.
+func WriteError(w http.ResponseWriter, err error) {
+	status := StatusCode(err)
+	msg := http.StatusText(status)
+	if consterr.ClassOf(err).UserFacing() {
+		msg = err.Error()
+	}
+	http.Error(w, msg, status)
+}
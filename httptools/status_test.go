@@ -0,0 +1,80 @@
+package httptools_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/matryer/is"
+
+	"importfromprojectlocally/consterr"
+	"importfromprojectlocally/httptools"
+)
+
+const (
+	errBadStream = consterr.Coded("invalid-input/bad-stream: stream name must be lowercase")
+	errNoStream  = consterr.Coded("not-found/no-stream: stream does not exist")
+	errBusy      = consterr.Coded("retryable/busy: too many requests in flight")
+	errDBDown    = consterr.Coded("internal/db-down: database password is hunter2")
+)
+
+func TestStatusCode(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		err    error
+		status int
+	}{
+		"nil":             {nil, http.StatusOK},
+		"invalid input":   {errBadStream, http.StatusBadRequest},
+		"not found":       {fmt.Errorf("getting: %w", errNoStream), http.StatusNotFound},
+		"conflict":        {consterr.Coded("conflict/exists: stream exists"), http.StatusConflict},
+		"unauthenticated": {consterr.Coded("unauthenticated/no-token: missing token"), http.StatusUnauthorized},
+		"forbidden":       {consterr.Coded("forbidden/read-only: stream is read only"), http.StatusForbidden},
+		"retryable":       {errBusy.Wrap(errors.New("queue full")), http.StatusServiceUnavailable},
+		"internal":        {errDBDown, http.StatusInternalServerError},
+		"unclassified":    {errors.New("boom"), http.StatusInternalServerError},
+		"unknown class":   {consterr.Coded("not-fund/no-stream: stream does not exist"), http.StatusInternalServerError},
+		"plain sentinel":  {consterr.Coded("plain").Wrap(errNoStream), http.StatusNotFound},
+		"deadline":        {fmt.Errorf("querying: %w", context.DeadlineExceeded), http.StatusServiceUnavailable},
+	}
+
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+			is.Equal(httptools.StatusCode(tc.err), tc.status)
+		})
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]struct {
+		err    error
+		status int
+		body   string
+	}{
+		"user facing": {errNoStream.With("stream", "logs"), http.StatusNotFound, "stream does not exist stream=logs\n"},
+		"internal":    {errDBDown, http.StatusInternalServerError, "Internal Server Error\n"},
+		"retryable":   {errBusy, http.StatusServiceUnavailable, "Service Unavailable\n"},
+	}
+
+	for name, tc := range testCases {
+		name, tc := name, tc
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			is := is.New(t)
+
+			rec := httptest.NewRecorder()
+			httptools.WriteError(rec, tc.err)
+			is.Equal(rec.Code, tc.status)
+			is.Equal(rec.Body.String(), tc.body)
+		})
+	}
+}
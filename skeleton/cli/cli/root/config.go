@@ -29,7 +29,8 @@ func (cfg *Config) CommonFlags(fs *flag.FlagSet) {
 }
 
 func (cfg *Config) WithLoggerCtx(ctx context.Context) context.Context {
-	log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+	// Coloured if stdout is a terminal, unless NO_COLOR is set.
+	log := slog.New(slogext.NewConsoleHandler(os.Stdout, &slogext.ConsoleOptions{
 		Level: cfg.LogLevel,
 	}))
 
@@ -11,6 +11,7 @@ import (
 	putcmd "myapp/cli/put"
 	"myapp/cli/root"
 	versioncmd "myapp/cli/version"
+	"myapp/envflag"
 )
 
 // Run executes the app.
@@ -26,7 +27,12 @@ func Run(ctx context.Context, args, env []string, stdin io.ReadCloser, stdout, s
 		versioncmd.New(cfg),
 	}
 
-	if err := rootCmd.Parse(args[1:]); err != nil {
+	// envflag parses the command tree rather than ffcli,
+	// so each flag can also be set from env, ie `myapp get -count` from MYAPP_GET_COUNT,
+	// with common flags like -level also read from MYAPP_LEVEL.
+	from := map[*envflag.Command]*ffcli.Command{}
+	cmd, cmdArgs, err := envflag.ParseCommand(envCommand(rootCmd, from), args[1:], env, envflag.WithFlagSetPrefix())
+	if err != nil {
 		return fmt.Errorf("parsing cli: %w", err)
 	}
 
@@ -34,5 +40,16 @@ func Run(ctx context.Context, args, env []string, stdin io.ReadCloser, stdout, s
 	// clients for apis, loggers, etc
 	ctx = cfg.WithLoggerCtx(ctx)
 
-	return rootCmd.Run(ctx)
+	return from[cmd].Exec(ctx, cmdArgs)
+}
+
+// envCommand mirrors an ffcli command tree for envflag.ParseCommand,
+// noting which ffcli command each came from.
+func envCommand(c *ffcli.Command, from map[*envflag.Command]*ffcli.Command) *envflag.Command {
+	ec := &envflag.Command{Name: c.Name, FlagSet: c.FlagSet}
+	from[ec] = c
+	for _, sub := range c.Subcommands {
+		ec.Subcommands = append(ec.Subcommands, envCommand(sub, from))
+	}
+	return ec
 }
@@ -16,6 +16,8 @@ import (
 	// The app package must offer:
 	// func Run(ctx context.Context, args, env []string, input io.ReadCloser, output, errout io.WriteCloser) error
 	app "myapp/cli"
+	"myapp/consterr"
+	"myapp/slogext"
 )
 
 func main() {
@@ -29,7 +31,7 @@ func main() {
 	time.Local = time.UTC
 	// Try to catch any logging from downstream libraries using either log.Print
 	// or module level slog.Info etc.
-	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	slog.SetDefault(slog.New(slogext.NewConsoleHandler(os.Stdout, nil)))
 
 	// calling os.Exit(1) directly from main() prevents the signal defer from running.
 	// Ensure that signal stop() runs, but os.Exit still gets the appropriate return code.
@@ -43,6 +45,7 @@ func main() {
 
 	if err := app.Run(sigctx, os.Args, os.Environ(), os.Stdin, os.Stdout, os.Stderr); err != nil {
 		fmt.Fprintf(os.Stderr, "%s\n", err)
-		exitCode = 1
+		// consterr.Coded errors map to sysexits codes by class, others to 1.
+		exitCode = consterr.ExitCode(err)
 	}
 }
@@ -17,11 +17,12 @@ const (
 
 // Config contains the service configuration as parsed from CLI flags, env
 // variables, and embedded build information.
+// Flags are registered from the field tags by envflag.ParseStruct.
 type Config struct {
-	LogLevel *slog.LevelVar
-	Port     int
+	LogLevel *slog.LevelVar `flag:"level" usage:"logging level (debug, info, warn, error)"`
+	Port     int            `default:"8000" usage:"network port to listen on"`
 
-	BuildInfo BuildInfo `json:"Build"`
+	BuildInfo BuildInfo `json:"Build" flag:"-"`
 }
 
 // NewConfig creates an application Config from command line flags and environment variables.
@@ -31,16 +32,14 @@ func NewConfig(args, env []string) (*Config, error) {
 	}
 
 	if len(args) == 0 {
-		return nil, ErrNoArgs
+		return nil, consterr.Trace(ErrNoArgs)
 	}
 
 	// ContinueOnError as to never panic or os.Exit() except at the top level
 	fs := flag.NewFlagSet(args[0], flag.ContinueOnError)
-	fs.TextVar(c.LogLevel, "level", &slog.LevelVar{}, "logging level (debug, info, warn, error)")
-	fs.IntVar(&c.Port, "port", 8000, "network port to listen on")
 
 	// envflag wraps fs.Parse to also pull from equiv ENV variables if no cli arg is set
-	if err := envflag.Parse(fs, args[1:], env); err != nil {
+	if err := envflag.ParseStruct(fs, &c, args[1:], env); err != nil {
 		return nil, fmt.Errorf("parsing config from environment: %w", err)
 	}
 
@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"time"
 
+	"myapp/envflag"
 	"myapp/slogext"
 )
 
@@ -28,6 +29,9 @@ func (a myapp) Run(ctx context.Context) error {
 
 	log.Info("starting")
 
+	// LogLevel can be changed without a restart via SIGHUP or editing the -config file.
+	go a.cfg.Reloader.Run(ctx, log, 5*time.Second)
+
 	log.Info("doing arbitrary things until shutdown signaled",
 		slog.Int("things", a.things),
 		slog.String("stuff", a.stuff),
@@ -57,11 +61,13 @@ func (a myapp) Run(ctx context.Context) error {
 // so that it can choose to set up an initial context for Run(),
 // and deal with any signal canceling/shutdown logic
 func newApp(ctx context.Context, output io.Writer, cfg *Config) (context.Context, *myapp) {
-	app := &myapp{things: 1, stuff: "foo"}
+	app := &myapp{cfg: cfg, things: 1, stuff: "foo"}
 
-	log := slog.New(slog.NewJSONHandler(output, &slog.HandlerOptions{
+	// Add any attrs from slogext.AddAttrs(ctx, ...) when logging with a context,
+	// and redact attrs such as passwords and tokens, however deeply they're logged.
+	log := slog.New(slogext.NewContextHandler(slogext.NewRedactHandler(slog.NewJSONHandler(output, &slog.HandlerOptions{
 		Level: cfg.LogLevel,
-	}))
+	}), nil)))
 	log.Debug("debug logging on.")
 
 	log.Debug("setting default context logger")
@@ -75,7 +81,9 @@ func newApp(ctx context.Context, output io.Writer, cfg *Config) (context.Context
 	log.Debug("setting up 3p SDKs")
 	log.Debug("etc")
 
-	log.Info("app setup complete", slog.Any("config", cfg))
+	// LogStruct masks any Config field tagged `envflag:"sensitive"` or of a SensitiveFlag type.
+	log.Info("app setup complete", slog.Any("config", envflag.LogStruct(cfg)))
+	envflag.LogProvenance(ctx, log, cfg.Sources)
 	return ctx, app
 }
 
@@ -17,33 +17,57 @@ const (
 
 // Config contains the service configuration as parsed from CLI flags, env
 // variables, and embedded build information.
+// Flags are registered from the field tags by envflag.Bind.
 type Config struct {
-	AppName  string
-	LogLevel *slog.LevelVar
-	Port     int
+	AppName    string         `flag:"-"`
+	ConfigFile string         `flag:"config" usage:"optional config file of name=value lines, or JSON if named *.json"`
+	LogLevel   *slog.LevelVar `envflag:"reloadable" usage:"logging level (debug, info, warn, error)"`
+	Port       int            `default:"8000" usage:"network port to listen on"`
+	// Add other fields here
 
-	BuildInfo BuildInfo `json:"Build"`
+	BuildInfo BuildInfo `json:"Build" flag:"-"`
+	// Sources records where each flag got its value, for startup logging.
+	Sources envflag.Provenance `json:"-" flag:"-"`
+	// Reloader updates reloadable fields on SIGHUP or when the config file changes.
+	Reloader *envflag.Reloader `json:"-" flag:"-"`
 }
 
 // NewConfig creates an application Config from command line flags and environment variables.
 func NewConfig(args []string, env []string) (*Config, error) {
 	if len(args) == 0 {
-		return nil, ErrNoArgs
+		return nil, consterr.Trace(ErrNoArgs)
 	}
 
 	c := Config{
 		AppName:   args[0],
 		BuildInfo: getBuildInfo(),
+		Reloader:  &envflag.Reloader{},
 	}
 
 	// ContinueOnError as to never panic or os.Exit() except at the top level.
 	fs := flag.NewFlagSet(c.AppName, flag.ContinueOnError)
-	fs.TextVar(c.LogLevel, "log-level", &slog.LevelVar{}, "logging level (debug, info, warn, error)")
-	fs.IntVar(&c.Port, "port", 8000, "network port to listen on")
-	// Add other fields here
+	opts, err := envflag.Bind(fs, &c)
+	if err != nil {
+		return nil, err
+	}
 
-	// envflag wraps fs.Parse to also pull from equiv ENV variables if no cli arg is set
-	if err := envflag.Parse(fs, args[1:], env); err != nil {
+	// envflag wraps fs.Parse to also pull from equiv ENV variables if no cli arg is set,
+	// or mounted secrets named by MYAPP_FOO_FILE,
+	// then from the -config file if one is given.
+	// Variables are namespaced by the binary name (MYAPP_PORT),
+	// falling back to the bare name (PORT) for older deployments.
+	opts = append(opts,
+		envflag.WithFlagSetPrefix(),
+		envflag.WithUnprefixedFallback(),
+		envflag.WithConfigFileFlag("config"),
+		envflag.WithSecretFiles(0),
+		envflag.WithProvenance(&c.Sources),
+		envflag.WithReloader(c.Reloader),
+		envflag.Range("port", 1, 65535),
+	)
+	// -h lists the env variable for each flag as well.
+	fs.Usage = envflag.Usage(fs, env, opts...)
+	if err := envflag.Parse(fs, args[1:], env, opts...); err != nil {
 		return nil, fmt.Errorf("parsing config from environment: %w", err)
 	}
 
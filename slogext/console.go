@@ -0,0 +1,272 @@
+package slogext
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// ANSI escape codes used by the ConsoleHandler.
+const (
+	ansiReset   = "\x1b[0m"
+	ansiBold    = "\x1b[1m"
+	ansiFaint   = "\x1b[2m"
+	ansiRed     = "\x1b[31m"
+	ansiGreen   = "\x1b[32m"
+	ansiYellow  = "\x1b[33m"
+	ansiMagenta = "\x1b[35m"
+)
+
+// ColorMode selects if a ConsoleHandler colours its output.
+type ColorMode int
+
+const (
+	// ColorAuto colours output if the writer is a terminal,
+	// unless the NO_COLOR env var is set, or if FORCE_COLOR is set and not 0.
+	ColorAuto ColorMode = iota
+	ColorNever
+	ColorAlways
+)
+
+// ConsoleOptions are options for a ConsoleHandler, as per slog.HandlerOptions.
+type ConsoleOptions struct {
+	// Level is the minimum level to log. If nil, it is slog.LevelInfo.
+	Level slog.Leveler
+	// Color selects if output is coloured with ANSI escape codes.
+	Color ColorMode
+	// ReplaceAttr is as per slog.HandlerOptions, including for the time, level, and message.
+	// Dropping the time gives deterministic output, such as for golden tests.
+	ReplaceAttr ReplaceAttrFunc
+}
+
+// ConsoleHandler is an slog.Handler for people reading logs in a terminal, such as from CLI tools.
+// Each record is one line of a short time, an aligned level badge, the message, then attrs,
+// with groups as dotted keys like slog.TextHandler.
+// Values spanning several lines, such as from consterr.Join, are instead listed beneath, indented.
+//
+//	15:04:05.000 INFO  starting app=myapp port=8000
+//	15:04:05.001 ERROR exiting app=myapp
+//	  error: 2 errors:
+//	    - first error
+//	    - second error
+type ConsoleHandler struct {
+	opts  ConsoleOptions
+	color bool
+	mu    *sync.Mutex
+	w     io.Writer
+
+	// attrs are those from WithAttrs, already formatted, with any multi-line values in block.
+	attrs []byte
+	block []byte
+	// groups are from WithGroup, for ReplaceAttr and the prefix of keys.
+	groups []string
+}
+
+// NewConsoleHandler returns a ConsoleHandler writing to w.
+// If opts is nil, the default options are used.
+func NewConsoleHandler(w io.Writer, opts *ConsoleOptions) *ConsoleHandler {
+	h := &ConsoleHandler{mu: &sync.Mutex{}, w: w}
+	if opts != nil {
+		h.opts = *opts
+	}
+	if h.opts.Level == nil {
+		h.opts.Level = slog.LevelInfo
+	}
+	switch h.opts.Color {
+	case ColorAlways:
+		h.color = true
+	case ColorAuto:
+		h.color = autoColor(w)
+	}
+	return h
+}
+
+// autoColor reports if w should be coloured, per [ColorAuto].
+func autoColor(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if force := os.Getenv("FORCE_COLOR"); force != "" && force != "0" {
+		return true
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Enabled reports if level is at least the minimum level.
+func (h *ConsoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.opts.Level.Level()
+}
+
+// Handle writes the record as a line, followed by any multi-line values.
+func (h *ConsoleHandler) Handle(_ context.Context, r slog.Record) error {
+	line, block := &bytes.Buffer{}, &bytes.Buffer{}
+
+	if t := h.replace(nil, slog.Time(slog.TimeKey, r.Time)); !r.Time.IsZero() && !t.Equal(slog.Attr{}) {
+		if t.Value.Kind() == slog.KindTime {
+			line.WriteString(h.paint(ansiFaint, t.Value.Time().Format("15:04:05.000")))
+		} else {
+			line.WriteString(h.paint(ansiFaint, t.Value.String()))
+		}
+		line.WriteByte(' ')
+	}
+	if l := h.replace(nil, slog.Any(slog.LevelKey, r.Level)); !l.Equal(slog.Attr{}) {
+		level, ok := l.Value.Any().(slog.Level)
+		if !ok {
+			level = r.Level
+		}
+		badge := l.Value.String()
+		line.WriteString(h.paint(levelColor(level), badge+strings.Repeat(" ", max(0, 5-len(badge)))))
+		line.WriteByte(' ')
+	}
+	if m := h.replace(nil, slog.String(slog.MessageKey, r.Message)); !m.Equal(slog.Attr{}) {
+		line.WriteString(h.paint(ansiBold, m.Value.String()))
+	}
+
+	line.Write(h.attrs)
+	block.Write(h.block)
+	prefix := groupPrefix(h.groups)
+	r.Attrs(func(a slog.Attr) bool {
+		h.appendAttr(line, block, h.groups, prefix, a)
+		return true
+	})
+	line.WriteByte('\n')
+	line.Write(block.Bytes())
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(line.Bytes())
+	return err
+}
+
+// WithAttrs returns a ConsoleHandler with the attrs formatted for every record.
+func (h *ConsoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	line, block := bytes.NewBuffer(slices.Clip(h.attrs)), bytes.NewBuffer(slices.Clip(h.block))
+	prefix := groupPrefix(h.groups)
+	for _, a := range attrs {
+		h.appendAttr(line, block, h.groups, prefix, a)
+	}
+	h2 := *h
+	h2.attrs, h2.block = line.Bytes(), block.Bytes()
+	return &h2
+}
+
+// WithGroup returns a ConsoleHandler with later attrs within the group.
+func (h *ConsoleHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.groups = append(slices.Clip(h.groups), name)
+	return &h2
+}
+
+// replace applies any ReplaceAttr option to a, within groups.
+func (h *ConsoleHandler) replace(groups []string, a slog.Attr) slog.Attr {
+	if h.opts.ReplaceAttr == nil {
+		return a
+	}
+	return h.opts.ReplaceAttr(groups, a)
+}
+
+// appendAttr formats a, within groups, as ` key=value` to line,
+// or `  key: value` to block if its value has several lines.
+func (h *ConsoleHandler) appendAttr(line, block *bytes.Buffer, groups []string, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindGroup {
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return
+		}
+		if a.Key != "" {
+			groups = append(slices.Clip(groups), a.Key)
+			prefix += a.Key + "."
+		}
+		for _, ga := range attrs {
+			h.appendAttr(line, block, groups, prefix, ga)
+		}
+		return
+	}
+
+	if a = h.replace(groups, a); a.Equal(slog.Attr{}) {
+		return
+	}
+	a.Value = a.Value.Resolve()
+	value, color := formatValue(a.Value)
+	if strings.Contains(value, "\n") {
+		block.WriteString("  " + h.paint(ansiFaint, prefix+a.Key+":") + " ")
+		block.WriteString(h.paint(color, strings.ReplaceAll(value, "\n", "\n    ")))
+		block.WriteByte('\n')
+		return
+	}
+	if needsQuoting(value) {
+		value = strconv.Quote(value)
+	}
+	line.WriteString(" " + h.paint(ansiFaint, prefix+a.Key+"=") + h.paint(color, value))
+}
+
+// paint wraps s in the colour, if colouring output.
+func (h *ConsoleHandler) paint(color, s string) string {
+	if !h.color || color == "" {
+		return s
+	}
+	return color + s + ansiReset
+}
+
+// formatValue renders v, and returns the colour for it, which is red for errors.
+func formatValue(v slog.Value) (string, string) {
+	switch v.Kind() {
+	case slog.KindTime:
+		return v.Time().Format("2006-01-02T15:04:05.000Z07:00"), ""
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return err.Error(), ansiRed
+		}
+	}
+	return v.String(), ""
+}
+
+// levelColor returns the colour of the badge for level.
+func levelColor(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return ansiRed
+	case level >= slog.LevelWarn:
+		return ansiYellow
+	case level >= slog.LevelInfo:
+		return ansiGreen
+	}
+	return ansiMagenta
+}
+
+// groupPrefix returns the dotted prefix for keys within groups.
+func groupPrefix(groups []string) string {
+	if len(groups) == 0 {
+		return ""
+	}
+	return strings.Join(groups, ".") + "."
+}
+
+// needsQuoting reports if s must be quoted to be read back unambiguously, as with slog.TextHandler.
+func needsQuoting(s string) bool {
+	if s == "" {
+		return true
+	}
+	for _, r := range s {
+		if unicode.IsSpace(r) || r == '"' || r == '=' || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package slogext_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"importfromprojectlocally/slogext"
+
+	"github.com/matryer/is"
+)
+
+func TestConsoleHandler(t *testing.T) {
+	is := is.New(t)
+
+	buf := &bytes.Buffer{}
+	log := slog.New(slogext.NewConsoleHandler(buf, &slogext.ConsoleOptions{
+		Level:       slog.LevelDebug,
+		Color:       slogext.ColorNever,
+		ReplaceAttr: slogext.ReplaceIf(slogext.MatchPath(slog.TimeKey), slogext.DropAttr),
+	}))
+
+	log.Debug("detail", "n", 1)
+	log.With("app", "myapp").WithGroup("req").Info("request done", "path", "/a b", slog.Group("user", "id", 7), "empty", "")
+	log.Warn("slow", "took", 1500*time.Millisecond, "at", time.Date(2023, 9, 16, 11, 37, 23, 42000000, time.UTC))
+	log.Error("exiting", "error", errors.Join(errors.New("first"), errors.New("second")), "code", 2)
+
+	is.Equal(buf.String(), "DEBUG detail n=1\n"+
+		"INFO  request done app=myapp req.path=\"/a b\" req.user.id=7 req.empty=\"\"\n"+
+		"WARN  slow took=1.5s at=2023-09-16T11:37:23.042Z\n"+
+		"ERROR exiting code=2\n"+
+		"  error: first\n"+
+		"    second\n")
+}
+
+func TestConsoleHandlerTime(t *testing.T) {
+	is := is.New(t)
+
+	buf := &bytes.Buffer{}
+	h := slogext.NewConsoleHandler(buf, &slogext.ConsoleOptions{Color: slogext.ColorNever})
+	r := slog.NewRecord(time.Date(2023, 9, 16, 11, 37, 23, 42000000, time.UTC), slog.LevelInfo, "test", 0)
+	is.NoErr(h.Handle(context.Background(), r))
+	is.Equal(buf.String(), "11:37:23.042 INFO  test\n")
+}
+
+func TestConsoleHandlerColor(t *testing.T) {
+	testCases := map[string]struct {
+		color    slogext.ColorMode
+		env      map[string]string
+		expected bool
+	}{
+		"never":              {slogext.ColorNever, map[string]string{"FORCE_COLOR": "1"}, false},
+		"always":             {slogext.ColorAlways, map[string]string{"NO_COLOR": "1"}, true},
+		"auto not terminal":  {slogext.ColorAuto, nil, false},
+		"auto forced":        {slogext.ColorAuto, map[string]string{"FORCE_COLOR": "1"}, true},
+		"auto forced off":    {slogext.ColorAuto, map[string]string{"FORCE_COLOR": "0"}, false},
+		"auto no color wins": {slogext.ColorAuto, map[string]string{"NO_COLOR": "1", "FORCE_COLOR": "1"}, false},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			is := is.New(t)
+			for _, key := range []string{"NO_COLOR", "FORCE_COLOR"} {
+				t.Setenv(key, tc.env[key])
+			}
+
+			buf := &bytes.Buffer{}
+			log := slog.New(slogext.NewConsoleHandler(buf, &slogext.ConsoleOptions{
+				Color:       tc.color,
+				ReplaceAttr: slogext.ReplaceIf(slogext.MatchPath(slog.TimeKey), slogext.DropAttr),
+			}))
+			log.Error("failed", "error", os.ErrNotExist)
+
+			colored := fmt.Sprintf("\x1b[31mERROR\x1b[0m \x1b[1mfailed\x1b[0m \x1b[2merror=\x1b[0m\x1b[31m%q\x1b[0m\n", os.ErrNotExist.Error())
+			if tc.expected {
+				is.Equal(buf.String(), colored)
+			} else {
+				is.Equal(buf.String(), fmt.Sprintf("ERROR failed error=%q\n", os.ErrNotExist.Error()))
+			}
+		})
+	}
+}
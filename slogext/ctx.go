@@ -3,15 +3,37 @@ package slogext
 import (
 	"context"
 	"log/slog"
+	"slices"
+	"sync/atomic"
 )
 
+// defaultContextLogger is swapped atomically, as From may be called from any goroutine.
+//
 //nolint:gochecknoglobals // deliberate package state
-var (
-	defaultContextLogger *slog.Logger
-)
+var defaultContextLogger atomic.Pointer[contextDefault]
+
+// contextDefault is the logger From falls back to,
+// or slog.Default() as it is at the time if followSlog is set.
+type contextDefault struct {
+	logger     *slog.Logger
+	followSlog bool
+}
+
+// get returns the logger, or nil if none has been set.
+func (d *contextDefault) get() *slog.Logger {
+	switch {
+	case d == nil:
+		return nil
+	case d.followSlog:
+		return slog.Default()
+	}
+	return d.logger
+}
 
 type ctxKey struct{}
 
+type attrsKey struct{}
+
 // From returns an *slog.Logger associated to a context.
 // If the context does not contain an existing logger,
 // if slogext.SetContextDefault has been called, returns that logger,
@@ -21,7 +43,7 @@ type ctxKey struct{}
 func From(ctx context.Context) *slog.Logger {
 	if l, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok {
 		return l
-	} else if l = defaultContextLogger; l != nil {
+	} else if l = defaultContextLogger.Load().get(); l != nil {
 		return l
 	}
 	return slog.New(DiscardHandler{})
@@ -38,18 +60,54 @@ func Add(ctx context.Context, l *slog.Logger) context.Context {
 }
 
 // GetContextDefault returns the *slog.Logger set by SetContextDefault,
-// or nil if that has not been called.
+// slog.Default() after UseSlogDefault, or nil if neither has been called.
 func GetContextDefault() *slog.Logger {
-	return defaultContextLogger
+	return defaultContextLogger.Load().get()
 }
 
 // SetContextDefault makes l the *slog.Logger returned by From when
 // a context has no previously associated logger.
+// It is safe to call while other goroutines call From.
 //
 //	slogext.SetContextDefault(mylogger)
 //	if logger := slogext.From(context.Background()); logger == mylogger {
 //	  logger.Info("successfully updated default context logger")
 //	}
 func SetContextDefault(l *slog.Logger) {
-	defaultContextLogger = l
+	defaultContextLogger.Store(&contextDefault{logger: l})
+}
+
+// UseSlogDefault makes From fall back to slog.Default(), as it is at the time,
+// rather than a separate logger set by SetContextDefault.
+//
+//	slog.SetDefault(mylogger)
+//	slogext.UseSlogDefault()
+func UseSlogDefault() {
+	defaultContextLogger.Store(&contextDefault{followSlog: true})
+}
+
+// OverrideContextDefault sets l as per SetContextDefault until the end of a test,
+// when the previous default, including from UseSlogDefault, is restored.
+//
+//	slogext.OverrideContextDefault(t, slog.New(slog.NewTextHandler(buf, nil)))
+func OverrideContextDefault(tb interface{ Cleanup(func()) }, l *slog.Logger) {
+	prev := defaultContextLogger.Swap(&contextDefault{logger: l})
+	tb.Cleanup(func() { defaultContextLogger.Store(prev) })
+}
+
+// AddAttrs creates a new context from an existing one with attrs appended to any already added,
+// for a ContextHandler to add to records logged with the context.
+// Unlike Add with From(ctx).With(...), this doesn't create a new logger at every layer.
+//
+//	ctx = slogext.AddAttrs(ctx, slog.String("user", user))
+//	slogext.From(ctx).InfoContext(ctx, "authorized") // user=...
+func AddAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	// clip so appending never overwrites the attrs of another context derived from ctx.
+	return context.WithValue(ctx, attrsKey{}, append(slices.Clip(ContextAttrs(ctx)), attrs...))
+}
+
+// ContextAttrs returns the attrs added to a context by AddAttrs, in order.
+func ContextAttrs(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	return attrs
 }
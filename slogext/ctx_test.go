@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"reflect"
 	"regexp"
+	"sync"
 	"testing"
 )
 
@@ -79,3 +80,77 @@ func TestDefault(t *testing.T) {
 	log2.Warn("something", "count", 3)
 	tl.HasLogged(`time=` + timeRegexp + ` level=WARN msg=something count=3`)
 }
+
+func TestOverrideContextDefault(t *testing.T) {
+	tl := newTestLogger(t)
+	prevDefault := slogext.GetContextDefault()
+
+	t.Run("override", func(t *testing.T) {
+		slogext.OverrideContextDefault(t, tl.logger)
+		if slogext.From(context.Background()) != tl.logger {
+			t.Error("From empty context did not return the overriding logger")
+		}
+	})
+
+	if slogext.GetContextDefault() != prevDefault {
+		t.Error("OverrideContextDefault did not restore the previous default after the test")
+	}
+}
+
+func TestUseSlogDefault(t *testing.T) {
+	tl := newTestLogger(t)
+	prevSlog := slog.Default()
+	defer slog.SetDefault(prevSlog)
+	slogext.OverrideContextDefault(t, nil) // restore the context default too.
+
+	slogext.UseSlogDefault()
+	slog.SetDefault(tl.logger) // after, as it follows slog.Default() as it changes.
+
+	if slogext.From(context.Background()) != tl.logger {
+		t.Error("From empty context did not return slog.Default()")
+	}
+}
+
+func TestContextDefaultConcurrent(t *testing.T) {
+	tl := newTestLogger(t)
+	slogext.OverrideContextDefault(t, nil)
+
+	// Run with -race to check swapping the default while it's in use.
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			slogext.SetContextDefault(tl.logger)
+		}()
+		go func() {
+			defer wg.Done()
+			_ = slogext.From(context.Background())
+		}()
+	}
+	wg.Wait()
+
+	if slogext.GetContextDefault() != tl.logger {
+		t.Error("GetContextDefault did not return the logger set")
+	}
+}
+
+func TestAddAttrs(t *testing.T) {
+	tl := newTestLogger(t)
+	log := slog.New(slogext.NewContextHandler(tl.logger.Handler(), slogext.ContextValue("request_id", requestIDKey{})))
+	ctx := context.WithValue(slogext.Add(context.Background(), log), requestIDKey{}, "1234")
+
+	base := slogext.AddAttrs(ctx, slog.String("user", "me"))
+	first := slogext.AddAttrs(base, slog.Int("step", 1))
+	second := slogext.AddAttrs(base, slog.Int("step", 2))
+	if attrs := slogext.ContextAttrs(base); len(attrs) != 1 {
+		t.Error("AddAttrs to a derived context changed the parent's attrs", attrs)
+	}
+
+	slogext.From(first).InfoContext(first, "first")
+	slogext.From(second).InfoContext(second, "second", "id", 7)
+	slogext.From(base).Info("no context")
+	tl.HasLogged(`msg=first user=me step=1 request_id=1234\n`)
+	tl.HasLogged(`msg=second id=7 user=me step=2 request_id=1234\n`)
+	tl.HasLogged(`msg="no context"\n`) // only logged with the context passed in
+}
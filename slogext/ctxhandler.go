@@ -0,0 +1,76 @@
+package slogext
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+)
+
+// ContextExtractor returns attrs from a context for a ContextHandler to add to records,
+// such as a request ID stored by HTTP middleware, or nothing if it isn't present.
+type ContextExtractor func(ctx context.Context) []slog.Attr
+
+// ContextValue returns a ContextExtractor for the value stored in a context under key,
+// as an attr with the given name, or nothing if there is no value.
+//
+//	slogext.ContextValue("request_id", requestIDKey{})
+func ContextValue(name string, key any) ContextExtractor {
+	return func(ctx context.Context) []slog.Attr {
+		if v := ctx.Value(key); v != nil {
+			return []slog.Attr{slog.Any(name, v)}
+		}
+		return nil
+	}
+}
+
+// ContextHandler is an slog.Handler which adds attrs from the context passed to Handle,
+// those added by AddAttrs then those returned by its extractors,
+// to each record, then passes it to another handler.
+// So `log.InfoContext(ctx, ...)` carries correlation fields such as request and trace IDs,
+// without needing a new logger for each request.
+// As with any record attrs, they are within any groups added by WithGroup.
+//
+//	log := slog.New(slogext.NewContextHandler(handler,
+//		slogext.ContextValue("request_id", requestIDKey{}),
+//		traceIDs, // a ContextExtractor returning trace_id and span_id
+//	))
+type ContextHandler struct {
+	next       slog.Handler
+	extractors []ContextExtractor
+}
+
+// NewContextHandler returns a ContextHandler passing records to next.
+func NewContextHandler(next slog.Handler, extractors ...ContextExtractor) *ContextHandler {
+	return &ContextHandler{next: next, extractors: extractors}
+}
+
+// Enabled reports if the next handler is enabled at level.
+func (h *ContextHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle adds the attrs from ctx to the record, then passes it to the next handler.
+func (h *ContextHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := slices.Clip(ContextAttrs(ctx)) // so appending doesn't share the context's
+	for _, extract := range h.extractors {
+		attrs = append(attrs, extract(ctx)...)
+	}
+	if len(attrs) > 0 {
+		r = r.Clone()
+		r.AddAttrs(attrs...)
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs returns a ContextHandler whose next handler has the attrs added.
+func (h *ContextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &ContextHandler{next: h.next.WithAttrs(attrs), extractors: h.extractors}
+}
+
+// WithGroup returns a ContextHandler whose next handler has the group added.
+func (h *ContextHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &ContextHandler{next: h.next.WithGroup(name), extractors: h.extractors}
+}
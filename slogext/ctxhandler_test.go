@@ -0,0 +1,49 @@
+package slogext_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"importfromprojectlocally/slogext"
+
+	"github.com/matryer/is"
+)
+
+type requestIDKey struct{}
+
+type traceKey struct{}
+
+type trace struct {
+	traceID, spanID string
+}
+
+func TestContextHandler(t *testing.T) {
+	is := is.New(t)
+
+	traceIDs := func(ctx context.Context) []slog.Attr {
+		tr, ok := ctx.Value(traceKey{}).(trace)
+		if !ok {
+			return nil
+		}
+		return []slog.Attr{slog.String("trace_id", tr.traceID), slog.String("span_id", tr.spanID)}
+	}
+
+	buf := &bytes.Buffer{}
+	noTime := &slog.HandlerOptions{ReplaceAttr: slogext.ReplaceIf(slogext.MatchPath(slog.TimeKey), slogext.DropAttr)}
+	log := slog.New(slogext.NewContextHandler(slog.NewTextHandler(buf, noTime),
+		slogext.ContextValue("request_id", requestIDKey{}),
+		traceIDs,
+	)).With("app", "myapp")
+
+	log.InfoContext(context.Background(), "no request")
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "1234")
+	log.InfoContext(ctx, "request")
+	ctx = context.WithValue(ctx, traceKey{}, trace{"abc", "def"})
+	log.WithGroup("req").InfoContext(ctx, "traced", "id", 1)
+
+	is.Equal(buf.String(), "level=INFO msg=\"no request\" app=myapp\n"+
+		"level=INFO msg=request app=myapp request_id=1234\n"+
+		"level=INFO msg=traced app=myapp req.id=1 req.request_id=1234 req.trace_id=abc req.span_id=def\n")
+}
@@ -1,3 +1,4 @@
 // Package slogext contains various slog extensions for injecting and retrieving
-// loggers from a context.Context, updating time field formats, and handling errors more easily
+// loggers from a context.Context, updating time field formats, composing ReplaceAttr funcs,
+// and handling errors more easily
 package slogext
@@ -1,10 +1,31 @@
 package slogext
 
-import "log/slog"
+import (
+	"errors"
+	"log/slog"
+)
 
 // Error is a convenience function creating an slog.Attr
 // with a fixed "error" key, and the given error value,
 // rather than having to repeatedly use slog.Any("error", error)
+//
+// An error which is an slog.LogValuer, such as consterr.Wrap creates, renders as its group.
+// If one is wrapped further down the chain, such as by fmt.Errorf("...: %w", err),
+// its group is used with "msg" replaced by the full error message.
+// That includes the "stack" group of frames recorded by consterr.Trace,
+// when built with `-tags consterrtrace`.
 func Error(err error) slog.Attr {
+	var lv slog.LogValuer
+	if _, ok := err.(slog.LogValuer); !ok && errors.As(err, &lv) {
+		if v := lv.LogValue().Resolve(); v.Kind() == slog.KindGroup {
+			attrs := []slog.Attr{slog.String("msg", err.Error())}
+			for _, a := range v.Group() {
+				if a.Key != "msg" {
+					attrs = append(attrs, a)
+				}
+			}
+			return slog.Attr{Key: "error", Value: slog.GroupValue(attrs...)}
+		}
+	}
 	return slog.Attr{Key: "error", Value: slog.AnyValue(err)}
 }
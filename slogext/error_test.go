@@ -2,6 +2,8 @@ package slogext_test
 
 import (
 	"errors"
+	"fmt"
+	"importfromprojectlocally/consterr"
 	"importfromprojectlocally/slogext"
 	"testing"
 )
@@ -12,3 +14,28 @@ func TestSlogError(t *testing.T) {
 	tl.logger.Error("something failed", slogext.Error(testerr))
 	tl.HasLogged(`error="` + testerr.Error() + `"`)
 }
+
+func TestSlogErrorWrapped(t *testing.T) {
+	tl := newTestLogger(t)
+	testerr := consterr.Err("my test error").Wrap(errors.New("cause"), "id", 7)
+	tl.logger.Error("something failed", slogext.Error(testerr))
+	tl.HasLogged(`error.msg="my test error id=7: cause" error.err="my test error" error.id=7 error.cause=cause`)
+}
+
+func TestSlogErrorWrappedChain(t *testing.T) {
+	tl := newTestLogger(t)
+	testerr := fmt.Errorf("outer: %w", consterr.Err("my test error").With("id", 7))
+	tl.logger.Error("something failed", slogext.Error(testerr))
+	tl.HasLogged(`error.msg="outer: my test error id=7" error.err="my test error" error.id=7( error\.stack\.\S+)*\n`) // with a stack if built with -tags consterrtrace
+}
+
+func TestSlogErrorTraced(t *testing.T) {
+	tl := newTestLogger(t)
+	testerr := fmt.Errorf("outer: %w", consterr.Trace(errors.New("my test error")))
+	tl.logger.Error("something failed", slogext.Error(testerr))
+	if !errors.As(testerr, new(*consterr.Traced)) {
+		tl.HasLogged(`error="outer: my test error"\n`) // not built with -tags consterrtrace
+		return
+	}
+	tl.HasLogged(`error.msg="outer: my test error" error.stack.0.func=importfromprojectlocally/slogext_test.TestSlogErrorTraced error.stack.0.file=\S+/slogext/error_test.go error.stack.0.line=\d+ `)
+}
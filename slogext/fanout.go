@@ -0,0 +1,67 @@
+package slogext
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+)
+
+// FanoutHandler is an slog.Handler which passes records to several handlers,
+// each enabled at its own level, such as text to stderr at info
+// as well as JSON to a file at debug.
+//
+//	log := slog.New(slogext.NewFanoutHandler(
+//		slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelInfo}),
+//		slog.NewJSONHandler(file, &slog.HandlerOptions{Level: slog.LevelDebug}),
+//	))
+type FanoutHandler struct {
+	handlers []slog.Handler
+}
+
+// NewFanoutHandler returns a FanoutHandler passing records to handlers.
+func NewFanoutHandler(handlers ...slog.Handler) *FanoutHandler {
+	return &FanoutHandler{handlers: handlers}
+}
+
+// Enabled reports if any of the handlers are enabled at level.
+func (h *FanoutHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, child := range h.handlers {
+		if child.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle passes a copy of the record to each handler enabled at its level.
+// A handler returning an error doesn't stop the others, and the errors are joined.
+func (h *FanoutHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, child := range h.handlers {
+		if child.Enabled(ctx, r.Level) {
+			errs = append(errs, child.Handle(ctx, r.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithAttrs returns a FanoutHandler with the attrs added to every handler.
+func (h *FanoutHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make([]slog.Handler, 0, len(h.handlers))
+	for _, child := range h.handlers {
+		handlers = append(handlers, child.WithAttrs(attrs))
+	}
+	return &FanoutHandler{handlers: handlers}
+}
+
+// WithGroup returns a FanoutHandler with the group added to every handler.
+func (h *FanoutHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	handlers := make([]slog.Handler, 0, len(h.handlers))
+	for _, child := range h.handlers {
+		handlers = append(handlers, child.WithGroup(name))
+	}
+	return &FanoutHandler{handlers: handlers}
+}
@@ -0,0 +1,58 @@
+package slogext_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"importfromprojectlocally/slogext"
+
+	"github.com/matryer/is"
+)
+
+// failHandler is enabled at every level, and fails to handle any record.
+type failHandler struct {
+	slogext.DiscardHandler
+}
+
+func (failHandler) Enabled(_ context.Context, _ slog.Level) bool { return true }
+
+func (failHandler) Handle(_ context.Context, r slog.Record) error {
+	return errors.New("failed " + r.Message)
+}
+
+func TestFanoutHandler(t *testing.T) {
+	is := is.New(t)
+	noTime := slogext.ReplaceIf(slogext.MatchPath(slog.TimeKey), slogext.DropAttr)
+
+	text, json := &bytes.Buffer{}, &bytes.Buffer{}
+	h := slogext.NewFanoutHandler(
+		slog.NewTextHandler(text, &slog.HandlerOptions{Level: slog.LevelInfo, ReplaceAttr: noTime}),
+		slog.NewJSONHandler(json, &slog.HandlerOptions{Level: slog.LevelDebug, ReplaceAttr: noTime}),
+	)
+	is.True(h.Enabled(context.Background(), slog.LevelDebug))    // as JSON is
+	is.True(!h.Enabled(context.Background(), slog.LevelDebug-1)) // as neither is
+
+	log := slog.New(h).With("app", "myapp").WithGroup("req")
+	log.Debug("detail", "id", 1)
+	log.Info("done", "id", 1)
+
+	is.Equal(text.String(), "level=INFO msg=done app=myapp req.id=1\n") // info only
+	is.Equal(json.String(), `{"level":"DEBUG","msg":"detail","app":"myapp","req":{"id":1}}`+"\n"+
+		`{"level":"INFO","msg":"done","app":"myapp","req":{"id":1}}`+"\n")
+}
+
+func TestFanoutHandlerErrors(t *testing.T) {
+	is := is.New(t)
+
+	buf := &bytes.Buffer{}
+	h := slogext.NewFanoutHandler(failHandler{}, slog.NewTextHandler(buf, nil), failHandler{})
+	r := slog.NewRecord(time.Now(), slog.LevelInfo, "test", 0)
+
+	err := h.Handle(context.Background(), r)
+	is.Equal(err.Error(), "failed test\nfailed test")        // both failures
+	is.True(bytes.Contains(buf.Bytes(), []byte("msg=test"))) // without stopping the others
+}
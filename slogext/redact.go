@@ -0,0 +1,138 @@
+package slogext
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+)
+
+// Redacted replaces the value of any attr masked by RedactAttr.
+const Redacted = "[redacted]"
+
+// Sensitive is implemented by any type holding a secret or PII,
+// such as a password or email type, so it is always redacted by a RedactHandler.
+type Sensitive interface {
+	IsSensitive() bool
+}
+
+// RedactAttr is a ReplaceAttrFunc which masks the value with [Redacted].
+// Empty strings are left empty, so it's still apparent if a secret is unset.
+func RedactAttr(_ []string, a slog.Attr) slog.Attr {
+	if v := a.Value.Resolve(); v.Kind() == slog.KindString && v.String() == "" {
+		return a
+	}
+	return slog.String(a.Key, Redacted)
+}
+
+// HashAttr is a ReplaceAttrFunc which replaces the value with a short SHA-256 hash,
+// so records can still be correlated by value without revealing it.
+func HashAttr(_ []string, a slog.Attr) slog.Attr {
+	sum := sha256.Sum256([]byte(a.Value.Resolve().String()))
+	return slog.String(a.Key, "sha256:"+hex.EncodeToString(sum[:8]))
+}
+
+// RedactOptions are options for a RedactHandler.
+type RedactOptions struct {
+	// Match selects the attrs to redact, by key or group path per [AttrMatcher].
+	// If nil, attrs with keys containing password, secret, token, authorization, or email,
+	// ignoring case, are redacted.
+	Match AttrMatcher
+	// Replace replaces the value of redacted attrs. If nil, it is [RedactAttr].
+	// [HashAttr] is an alternative.
+	Replace ReplaceAttrFunc
+}
+
+// RedactHandler is an slog.Handler which redacts attrs before passing records to another.
+// Attrs added by WithAttrs, as from log.With, are redacted as they are added,
+// and attrs within groups, including from WithGroup, are matched by their full group path.
+type RedactHandler struct {
+	next    slog.Handler
+	match   AttrMatcher
+	replace ReplaceAttrFunc
+	groups  []string
+}
+
+// NewRedactHandler returns a RedactHandler passing records to next.
+// If opts is nil, the default options are used.
+//
+//	log := slog.New(slogext.NewRedactHandler(slog.NewJSONHandler(os.Stdout, nil), nil))
+//	log.Info("login", "user", "me", "password", pw) // password="[redacted]"
+func NewRedactHandler(next slog.Handler, opts *RedactOptions) *RedactHandler {
+	h := &RedactHandler{
+		next:    next,
+		match:   MatchKeyContains("password", "secret", "token", "authorization", "email"),
+		replace: RedactAttr,
+	}
+	if opts != nil && opts.Match != nil {
+		h.match = opts.Match
+	}
+	if opts != nil && opts.Replace != nil {
+		h.replace = opts.Replace
+	}
+	return h
+}
+
+// Enabled reports if the next handler is enabled at level.
+func (h *RedactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle redacts the record's attrs, then passes it to the next handler.
+func (h *RedactHandler) Handle(ctx context.Context, r slog.Record) error {
+	redacted := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		redacted.AddAttrs(h.redact(h.groups, a))
+		return true
+	})
+	return h.next.Handle(ctx, redacted)
+}
+
+// WithAttrs returns a RedactHandler whose next handler has the attrs added, redacted.
+func (h *RedactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		redacted = append(redacted, h.redact(h.groups, a))
+	}
+	h2 := *h
+	h2.next = h.next.WithAttrs(redacted)
+	return &h2
+}
+
+// WithGroup returns a RedactHandler whose next handler has the group added,
+// matching later attrs within it.
+func (h *RedactHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.next = h.next.WithGroup(name)
+	h2.groups = append(h.groups[:len(h.groups):len(h.groups)], name)
+	return &h2
+}
+
+// redact replaces a, within groups, if it matches or is Sensitive,
+// otherwise redacting within it if it is a group.
+func (h *RedactHandler) redact(groups []string, a slog.Attr) slog.Attr {
+	if s, ok := a.Value.Any().(Sensitive); ok && s.IsSensitive() {
+		return h.replace(groups, a)
+	}
+	a.Value = a.Value.Resolve()
+	if s, ok := a.Value.Any().(Sensitive); (ok && s.IsSensitive()) || h.match(groups, a) {
+		return h.replace(groups, a)
+	}
+	if a.Value.Kind() != slog.KindGroup {
+		return a
+	}
+
+	within := groups
+	if a.Key != "" {
+		within = append(groups[:len(groups):len(groups)], a.Key)
+	}
+	attrs := a.Value.Group()
+	redacted := make([]slog.Attr, 0, len(attrs))
+	for _, ga := range attrs {
+		redacted = append(redacted, h.redact(within, ga))
+	}
+	return slog.Attr{Key: a.Key, Value: slog.GroupValue(redacted...)}
+}
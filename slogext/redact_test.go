@@ -0,0 +1,77 @@
+package slogext_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"importfromprojectlocally/slogext"
+
+	"github.com/matryer/is"
+)
+
+type password string
+
+func (password) IsSensitive() bool { return true }
+
+func TestRedactHandler(t *testing.T) {
+	noTime := &slog.HandlerOptions{ReplaceAttr: slogext.ReplaceIf(slogext.MatchPath(slog.TimeKey), slogext.DropAttr)}
+
+	testCases := map[string]struct {
+		opts     *slogext.RedactOptions
+		log      func(log *slog.Logger)
+		expected string
+	}{
+		"record attrs": {
+			nil,
+			func(log *slog.Logger) {
+				log.Info("login", "user", "me", "Password", "hunter2", "X-Auth-Token", "abc", "email", "me@example.com")
+			},
+			`level=INFO msg=login user=me Password=[redacted] X-Auth-Token=[redacted] email=[redacted]`,
+		},
+		"empty left empty": {
+			nil,
+			func(log *slog.Logger) { log.Info("login", "password", "") },
+			`level=INFO msg=login password=""`,
+		},
+		"with attrs": {
+			nil,
+			func(log *slog.Logger) { log.With("token", "abc").Info("call", "id", 1) },
+			`level=INFO msg=call token=[redacted] id=1`,
+		},
+		"nested groups": {
+			nil,
+			func(log *slog.Logger) {
+				log.WithGroup("req").With(slog.Group("headers", "Authorization", "Bearer abc")).
+					Info("call", slog.Group("user", "name", "me", "email", "me@example.com"))
+			},
+			`level=INFO msg=call req.headers.Authorization=[redacted] req.user.name=me req.user.email=[redacted]`,
+		},
+		"sensitive type": {
+			nil,
+			func(log *slog.Logger) { log.Info("login", "pw", password("hunter2")) },
+			`level=INFO msg=login pw=[redacted]`,
+		},
+		"group path": {
+			&slogext.RedactOptions{Match: slogext.MatchPath("req", "user", "*")},
+			func(log *slog.Logger) {
+				log.WithGroup("req").Info("call", "token", "abc", slog.Group("user", "name", "me"))
+			},
+			`level=INFO msg=call req.token=abc req.user.name=[redacted]`,
+		},
+		"hashed": {
+			&slogext.RedactOptions{Replace: slogext.HashAttr},
+			func(log *slog.Logger) { log.Info("login", "email", "me@example.com", "pw", password("hunter2")) },
+			`level=INFO msg=login email=sha256:8c2a47d3bdb8d309 pw=sha256:f52fbd32b2b3b86f`,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			is := is.New(t)
+			buf := &bytes.Buffer{}
+			tc.log(slog.New(slogext.NewRedactHandler(slog.NewTextHandler(buf, noTime), tc.opts)))
+			is.Equal(buf.String(), tc.expected+"\n")
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package slogext
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// ReplaceAttrFunc is the signature of slog.HandlerOptions.ReplaceAttr,
+// such as RFC3339Millis.
+type ReplaceAttrFunc func(groups []string, a slog.Attr) slog.Attr
+
+// AttrMatcher reports if a, within the groups given to a ReplaceAttrFunc, should be replaced.
+type AttrMatcher func(groups []string, a slog.Attr) bool
+
+// ChainReplaceAttr combines fns into one slog.HandlerOptions.ReplaceAttr,
+// running each in order on the attr returned by the one before.
+// Once any fn drops the attr by returning the zero slog.Attr, the rest are skipped.
+//
+//	ReplaceAttr: slogext.ChainReplaceAttr(
+//		slogext.ReplaceIf(slogext.MatchKey("password"), slogext.DropAttr),
+//		slogext.ReplaceIf(slogext.MatchPath(slog.MessageKey), slogext.RenameAttr("message")),
+//		slogext.RFC3339Millis,
+//	),
+func ChainReplaceAttr(fns ...ReplaceAttrFunc) ReplaceAttrFunc {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		for _, fn := range fns {
+			if a = fn(groups, a); a.Equal(slog.Attr{}) {
+				return a
+			}
+		}
+		return a
+	}
+}
+
+// ReplaceIf returns a ReplaceAttrFunc applying fn only to attrs which match.
+func ReplaceIf(match AttrMatcher, fn ReplaceAttrFunc) ReplaceAttrFunc {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		if match(groups, a) {
+			return fn(groups, a)
+		}
+		return a
+	}
+}
+
+// DropAttr is a ReplaceAttrFunc which removes every attr, for use with ReplaceIf.
+func DropAttr(_ []string, _ slog.Attr) slog.Attr {
+	return slog.Attr{}
+}
+
+// RenameAttr returns a ReplaceAttrFunc which changes every attr's key, for use with ReplaceIf.
+func RenameAttr(key string) ReplaceAttrFunc {
+	return func(_ []string, a slog.Attr) slog.Attr {
+		a.Key = key
+		return a
+	}
+}
+
+// MatchKey matches attrs with any of keys, within any group.
+func MatchKey(keys ...string) AttrMatcher {
+	return func(_ []string, a slog.Attr) bool {
+		return slices.Contains(keys, a.Key)
+	}
+}
+
+// MatchKeyContains matches attrs with a key containing any of parts, ignoring case,
+// within any group, such that "token" matches "X-Auth-Token".
+func MatchKeyContains(parts ...string) AttrMatcher {
+	return func(_ []string, a slog.Attr) bool {
+		key := strings.ToLower(a.Key)
+		for _, p := range parts {
+			if strings.Contains(key, strings.ToLower(p)) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchPath matches the attr at the path of group names then key,
+// where "*" matches any one group name or key.
+// Note that slog's built in attrs, such as slog.TimeKey, are only at the top level.
+//
+//	slogext.MatchPath("request", "*", "token")
+func MatchPath(path ...string) AttrMatcher {
+	return func(groups []string, a slog.Attr) bool {
+		return len(groups)+1 == len(path) && matchGroups(path[:len(groups)], groups) &&
+			(path[len(groups)] == "*" || path[len(groups)] == a.Key)
+	}
+}
+
+// MatchGroup matches every attr within the group path, at any depth,
+// where "*" matches any one group name.
+func MatchGroup(groups ...string) AttrMatcher {
+	return func(within []string, _ slog.Attr) bool {
+		return len(within) >= len(groups) && matchGroups(groups, within[:len(groups)])
+	}
+}
+
+// MatchKind matches attrs with a value of any of kinds,
+// such as slog.KindTime for every time, not just the record's.
+func MatchKind(kinds ...slog.Kind) AttrMatcher {
+	return func(_ []string, a slog.Attr) bool {
+		return slices.Contains(kinds, a.Value.Kind())
+	}
+}
+
+// MatchAll matches attrs which every one of matchers match.
+func MatchAll(matchers ...AttrMatcher) AttrMatcher {
+	return func(groups []string, a slog.Attr) bool {
+		for _, m := range matchers {
+			if !m(groups, a) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// MatchAny matches attrs which any of matchers match.
+func MatchAny(matchers ...AttrMatcher) AttrMatcher {
+	return func(groups []string, a slog.Attr) bool {
+		for _, m := range matchers {
+			if m(groups, a) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// matchGroups reports if groups equals pattern, where "*" in pattern matches any name.
+func matchGroups(pattern, groups []string) bool {
+	for i, p := range pattern {
+		if p != "*" && p != groups[i] {
+			return false
+		}
+	}
+	return true
+}
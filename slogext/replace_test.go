@@ -0,0 +1,77 @@
+package slogext_test
+
+import (
+	"bytes"
+	"log/slog"
+	"regexp"
+	"testing"
+	"time"
+
+	"importfromprojectlocally/slogext"
+
+	"github.com/matryer/is"
+)
+
+func TestMatchers(t *testing.T) {
+	testCases := map[string]struct {
+		match    slogext.AttrMatcher
+		groups   []string
+		attr     slog.Attr
+		expected bool
+	}{
+		"key at top":          {slogext.MatchKey("a", "b"), nil, slog.Int("b", 1), true},
+		"key in group":        {slogext.MatchKey("a"), []string{"g"}, slog.Int("a", 1), true},
+		"key differs":         {slogext.MatchKey("a"), nil, slog.Int("c", 1), false},
+		"path":                {slogext.MatchPath("g", "a"), []string{"g"}, slog.Int("a", 1), true},
+		"path wildcard":       {slogext.MatchPath("*", "h", "*"), []string{"g", "h"}, slog.Int("a", 1), true},
+		"path too shallow":    {slogext.MatchPath("a"), []string{"g"}, slog.Int("a", 1), false},
+		"path too deep":       {slogext.MatchPath("g", "h", "a"), []string{"g"}, slog.Int("a", 1), false},
+		"path group differs":  {slogext.MatchPath("g", "a"), []string{"h"}, slog.Int("a", 1), false},
+		"group":               {slogext.MatchGroup("g"), []string{"g", "h"}, slog.Int("a", 1), true},
+		"group wildcard":      {slogext.MatchGroup("*", "h"), []string{"g", "h"}, slog.Int("a", 1), true},
+		"group at top":        {slogext.MatchGroup("g"), nil, slog.Int("g", 1), false},
+		"kind":                {slogext.MatchKind(slog.KindTime), []string{"g"}, slog.Time("a", time.Now()), true},
+		"kind differs":        {slogext.MatchKind(slog.KindTime), nil, slog.Int("a", 1), false},
+		"all":                 {slogext.MatchAll(slogext.MatchKey("a"), slogext.MatchGroup("g")), []string{"g"}, slog.Int("a", 1), true},
+		"all but one":         {slogext.MatchAll(slogext.MatchKey("a"), slogext.MatchGroup("g")), nil, slog.Int("a", 1), false},
+		"any":                 {slogext.MatchAny(slogext.MatchKey("b"), slogext.MatchGroup("g")), []string{"g"}, slog.Int("a", 1), true},
+		"any with none":       {slogext.MatchAny(), nil, slog.Int("a", 1), false},
+		"all with none":       {slogext.MatchAll(), nil, slog.Int("a", 1), true},
+		"any but none match":  {slogext.MatchAny(slogext.MatchKey("b"), slogext.MatchGroup("g")), nil, slog.Int("a", 1), false},
+		"path at top for key": {slogext.MatchPath(slog.TimeKey), nil, slog.Time(slog.TimeKey, time.Now()), true},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			is := is.New(t)
+			is.Equal(tc.match(tc.groups, tc.attr), tc.expected)
+		})
+	}
+}
+
+func TestChainReplaceAttr(t *testing.T) {
+	is := is.New(t)
+
+	calls := 0
+	counter := func(_ []string, a slog.Attr) slog.Attr {
+		calls++
+		return a
+	}
+
+	buf := &bytes.Buffer{}
+	log := slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		ReplaceAttr: slogext.ChainReplaceAttr(
+			slogext.ReplaceIf(slogext.MatchKey("password"), slogext.DropAttr),
+			slogext.ReplaceIf(slogext.MatchPath(slog.MessageKey), slogext.RenameAttr("message")),
+			slogext.ReplaceIf(slogext.MatchPath("req", "id"), slogext.RenameAttr("request_id")),
+			slogext.RFC3339Millis,
+			counter,
+		),
+	}))
+	log.WithGroup("req").Info("test", "id", 7, "password", "hunter2", slog.Group("user", "password", "x", "name", "me"))
+
+	ok, err := regexp.Match(`^time=`+rfc3339MilliRegexp+` level=INFO message=test req.request_id=7 req.user.name=me\n$`, buf.Bytes())
+	is.NoErr(err)
+	is.True(ok)        // passwords dropped at any depth, and the rest replaced in order
+	is.Equal(calls, 5) // not called after dropping
+}
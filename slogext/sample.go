@@ -0,0 +1,199 @@
+package slogext
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// SampleOptions are options for a SampleHandler.
+type SampleOptions struct {
+	// First is how many records with each level and message are passed each Interval.
+	First int
+	// Thereafter is how often later records are passed, ie 1 in every Thereafter.
+	// If 0, every record after First is dropped for the rest of the Interval.
+	Thereafter int
+	// Interval is how often the counts reset. If 0, it is 1 second.
+	Interval time.Duration
+	// Now returns the current time, for tests. If nil, it is time.Now.
+	Now func() time.Time
+	// AfterFunc calls f after d, for tests. If nil, it is time.AfterFunc.
+	AfterFunc func(d time.Duration, f func())
+}
+
+// SampleHandler is an slog.Handler which passes on a sample of records
+// with the same level and message, such as in a retry storm, to another.
+// The first records in each interval are passed on, then only 1 in every few, per [SampleOptions].
+// A summary of how many were dropped is logged at warn level
+// at the end of each interval in which any were, or by Flush.
+// It is safe for concurrent use, and handlers from WithAttrs and WithGroup share its counts.
+type SampleHandler struct {
+	next    slog.Handler
+	sampler *sampler
+}
+
+// sampler holds the counts shared by a SampleHandler and those derived from it.
+type sampler struct {
+	opts SampleOptions
+	// root is the handler given to NewSampleHandler, without any attrs or groups, to log summaries.
+	root slog.Handler
+
+	mu      sync.Mutex
+	start   time.Time
+	counts  map[sampleKey]int
+	dropped map[sampleKey]int
+	// scheduled is set while a summary is due at the end of the interval.
+	scheduled bool
+}
+
+type sampleKey struct {
+	level slog.Level
+	msg   string
+}
+
+// NewSampleHandler returns a SampleHandler passing records to next.
+// If opts is nil, the first 100 records each second are passed, then 1 in every 100.
+//
+//	log := slog.New(slogext.NewSampleHandler(handler, &slogext.SampleOptions{First: 10, Thereafter: 50}))
+func NewSampleHandler(next slog.Handler, opts *SampleOptions) *SampleHandler {
+	o := SampleOptions{First: 100, Thereafter: 100}
+	if opts != nil {
+		o = *opts
+	}
+	if o.Interval <= 0 {
+		o.Interval = time.Second
+	}
+	if o.Now == nil {
+		o.Now = time.Now
+	}
+	if o.AfterFunc == nil {
+		o.AfterFunc = func(d time.Duration, f func()) { time.AfterFunc(d, f) }
+	}
+	return &SampleHandler{
+		next: next,
+		sampler: &sampler{
+			opts:    o,
+			root:    next,
+			counts:  map[sampleKey]int{},
+			dropped: map[sampleKey]int{},
+		},
+	}
+}
+
+// Enabled reports if the next handler is enabled at level.
+func (h *SampleHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle passes the record to the next handler if it is sampled,
+// after any summary of records dropped in the previous interval.
+func (h *SampleHandler) Handle(ctx context.Context, r slog.Record) error {
+	keep, summary := h.sampler.sample(sampleKey{r.Level, r.Message})
+	err := h.sampler.log(ctx, summary)
+	if keep {
+		err = errors.Join(err, h.next.Handle(ctx, r))
+	}
+	return err
+}
+
+// Flush logs a summary of any records dropped so far in the current interval,
+// such as before exiting.
+func (h *SampleHandler) Flush(ctx context.Context) error {
+	return h.sampler.flush(ctx)
+}
+
+// WithAttrs returns a SampleHandler whose next handler has the attrs added.
+func (h *SampleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SampleHandler{next: h.next.WithAttrs(attrs), sampler: h.sampler}
+}
+
+// WithGroup returns a SampleHandler whose next handler has the group added.
+func (h *SampleHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &SampleHandler{next: h.next.WithGroup(name), sampler: h.sampler}
+}
+
+// sample counts a record, reporting if it should be kept,
+// and returning a summary if a new interval started after records were dropped.
+func (s *sampler) sample(key sampleKey) (bool, *slog.Record) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var summary *slog.Record
+	if now := s.opts.Now(); now.Sub(s.start) >= s.opts.Interval {
+		summary = s.summarize()
+		clear(s.counts)
+		s.start = now
+	}
+
+	s.counts[key]++
+	n := s.counts[key] - s.opts.First
+	if n <= 0 || (s.opts.Thereafter > 0 && n%s.opts.Thereafter == 0) {
+		return true, summary
+	}
+	s.dropped[key]++
+	if !s.scheduled {
+		s.scheduled = true
+		s.opts.AfterFunc(s.start.Add(s.opts.Interval).Sub(s.opts.Now()), func() {
+			_ = s.flush(context.Background())
+		})
+	}
+	return false, summary
+}
+
+// flush logs a summary of the records dropped so far.
+func (s *sampler) flush(ctx context.Context) error {
+	s.mu.Lock()
+	s.scheduled = false
+	summary := s.summarize()
+	s.mu.Unlock()
+	return s.log(ctx, summary)
+}
+
+// summarize returns a record of the dropped counts, by level and message, then resets them.
+// It returns nil if none were dropped. s.mu must be held.
+func (s *sampler) summarize() *slog.Record {
+	if len(s.dropped) == 0 {
+		return nil
+	}
+	keys := make([]sampleKey, 0, len(s.dropped))
+	total := 0
+	for key, n := range s.dropped {
+		keys = append(keys, key)
+		total += n
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].level != keys[j].level {
+			return keys[i].level < keys[j].level
+		}
+		return keys[i].msg < keys[j].msg
+	})
+	// a list of groups keyed by index, as messages may contain anything.
+	messages := make([]any, 0, len(keys))
+	for i, key := range keys {
+		messages = append(messages, slog.Group(strconv.Itoa(i),
+			slog.String("level", key.level.String()),
+			slog.String("msg", key.msg),
+			slog.Int("dropped", s.dropped[key]),
+		))
+	}
+	clear(s.dropped)
+
+	r := slog.NewRecord(s.opts.Now(), slog.LevelWarn, "log records dropped by sampling", 0)
+	r.AddAttrs(slog.Int("dropped", total), slog.Group("messages", messages...))
+	return &r
+}
+
+// log passes a summary, if any, to the root handler.
+func (s *sampler) log(ctx context.Context, summary *slog.Record) error {
+	if summary == nil || !s.root.Enabled(ctx, summary.Level) {
+		return nil
+	}
+	return s.root.Handle(ctx, *summary)
+}
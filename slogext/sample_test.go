@@ -0,0 +1,96 @@
+package slogext_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"importfromprojectlocally/slogext"
+
+	"github.com/matryer/is"
+)
+
+func TestSampleHandler(t *testing.T) {
+	is := is.New(t)
+
+	now := time.Date(2023, 9, 16, 11, 37, 23, 0, time.UTC)
+	timers := []func(){}
+	buf := &bytes.Buffer{}
+	noTime := &slog.HandlerOptions{ReplaceAttr: slogext.ReplaceIf(slogext.MatchPath(slog.TimeKey), slogext.DropAttr)}
+	h := slogext.NewSampleHandler(slog.NewTextHandler(buf, noTime), &slogext.SampleOptions{
+		First:      2,
+		Thereafter: 3,
+		Interval:   time.Second,
+		Now:        func() time.Time { return now },
+		AfterFunc: func(d time.Duration, f func()) {
+			is.Equal(d, 500*time.Millisecond) // until the end of the interval
+			timers = append(timers, f)
+		},
+	})
+	log := slog.New(h).With("app", "myapp")
+
+	log.Info("retrying", "attempt", 1)
+	now = now.Add(500 * time.Millisecond)
+	for i := 2; i <= 8; i++ {
+		log.Info("retrying", "attempt", i)
+	}
+	log.Warn("giving up")
+	is.Equal(strings.Count(buf.String(), "msg=retrying"), 4)     // first 2, then 1 in 3
+	is.True(strings.Contains(buf.String(), "attempt=5\n"))       // the 3rd after the first 2
+	is.True(strings.Contains(buf.String(), "msg=\"giving up\"")) // counted separately
+	is.True(!strings.Contains(buf.String(), "dropped"))          // not until the interval ends
+	is.Equal(len(timers), 1)                                     // one summary per interval
+
+	buf.Reset()
+	now = now.Add(500 * time.Millisecond)
+	timers[0]()
+	is.Equal(buf.String(), `level=WARN msg="log records dropped by sampling" dropped=4 `+
+		`messages.0.level=INFO messages.0.msg=retrying messages.0.dropped=4`+"\n") // logged at the end of the interval
+
+	buf.Reset()
+	log.Info("retrying", "attempt", 9)
+	is.Equal(buf.String(), `level=INFO msg=retrying app=myapp attempt=9`+"\n") // counts reset
+
+	buf.Reset()
+	now = now.Add(500 * time.Millisecond)
+	for i := 10; i <= 12; i++ {
+		log.Info("retrying", "attempt", i)
+	}
+	now = now.Add(500 * time.Millisecond)
+	log.Info("retrying", "attempt", 13)
+	is.Equal(buf.String(), `level=INFO msg=retrying app=myapp attempt=10`+"\n"+
+		`level=WARN msg="log records dropped by sampling" dropped=2 `+
+		`messages.0.level=INFO messages.0.msg=retrying messages.0.dropped=2`+"\n"+
+		`level=INFO msg=retrying app=myapp attempt=13`+"\n") // a late timer is preempted by the next record
+
+	buf.Reset()
+	timers[1]()
+	is.NoErr(h.Flush(context.Background()))
+	is.Equal(buf.String(), "") // nothing dropped since
+}
+
+func TestSampleHandlerDropAfterFirst(t *testing.T) {
+	is := is.New(t)
+
+	buf := &bytes.Buffer{}
+	h := slogext.NewSampleHandler(slog.NewTextHandler(buf, nil), &slogext.SampleOptions{First: 1})
+	log := slog.New(h)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			log.Info("hot path")
+		}()
+	}
+	wg.Wait()
+	is.Equal(strings.Count(buf.String(), "msg=\"hot path\""), 1) // only the first
+
+	is.NoErr(h.Flush(context.Background()))
+	is.True(strings.Contains(buf.String(), `dropped=49 messages.0.level=INFO messages.0.msg="hot path" messages.0.dropped=49`))
+}